@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/apigatewaymanagementapi"
 	"github.com/ksanta/word-stallion/model"
@@ -40,3 +41,19 @@ func (apiDao *ApiDao) SendMessageToPlayer(player model.Player, message interface
 	_, err = apiDao.service.PostToConnection(postToConnectionInput)
 	return err
 }
+
+// IsConnected returns true if the connection is still open
+func (apiDao *ApiDao) IsConnected(connectionId string) (bool, error) {
+	getConnectionInput := &apigatewaymanagementapi.GetConnectionInput{
+		ConnectionId: aws.String(connectionId),
+	}
+
+	_, err := apiDao.service.GetConnection(getConnectionInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == apigatewaymanagementapi.ErrCodeGoneException {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
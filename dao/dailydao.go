@@ -0,0 +1,142 @@
+package dao
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/ksanta/word-stallion/model"
+	"strconv"
+	"time"
+)
+
+type DailyDao struct {
+	service   *dynamodb.DynamoDB
+	tableName *string
+}
+
+func NewDailyDao(tableName string) *DailyDao {
+	mySession := session.Must(session.NewSession())
+
+	return &DailyDao{
+		service:   dynamodb.New(mySession),
+		tableName: aws.String(tableName),
+	}
+}
+
+// dailyChallengeLifetime is how long a daily challenge and its leaderboard are kept
+const dailyChallengeLifetime = 7 * 24 * time.Hour
+
+// ClaimDailyChallenge records that the player has started the date's challenge, creating the
+// challenge with the seed and words version if this is its first play. Returns false if the
+// player has already started it.
+func (dailyDao *DailyDao) ClaimDailyChallenge(date string, playerId string, wordsVersion string) (*model.DailyChallenge, bool, error) {
+	updateItemInput := &dynamodb.UpdateItemInput{
+		TableName: dailyDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"daily_date": {
+				S: aws.String(date),
+			},
+		},
+		UpdateExpression: aws.String("SET seed = if_not_exists(seed, :seed), " +
+			"words_version = if_not_exists(words_version, :wordsVersion), " +
+			"player_ids = list_append(if_not_exists(player_ids, :empty), :playerIds), " +
+			"expires_at = if_not_exists(expires_at, :expiresAt)"),
+		ConditionExpression: aws.String("NOT contains(player_ids, :playerId)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":seed": {
+				N: aws.String(strconv.FormatInt(model.DailySeed(date), 10)),
+			},
+			":wordsVersion": {
+				S: aws.String(wordsVersion),
+			},
+			":empty": {
+				L: []*dynamodb.AttributeValue{},
+			},
+			":playerIds": {
+				L: []*dynamodb.AttributeValue{{S: aws.String(playerId)}},
+			},
+			":playerId": {
+				S: aws.String(playerId),
+			},
+			":expiresAt": {
+				N: aws.String(strconv.FormatInt(time.Now().Add(dailyChallengeLifetime).Unix(), 10)),
+			},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllNew),
+	}
+
+	output, err := dailyDao.service.UpdateItem(updateItemInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	challenge := &model.DailyChallenge{}
+	err = dynamodbattribute.UnmarshalMap(output.Attributes, challenge)
+	if err != nil {
+		return nil, false, err
+	}
+	return challenge, true, nil
+}
+
+// AddDailyResult adds a player's result to the date's leaderboard
+func (dailyDao *DailyDao) AddDailyResult(date string, result model.DailyResult) error {
+	marshalledResult, err := dynamodbattribute.Marshal(result)
+	if err != nil {
+		return err
+	}
+	updateItemInput := &dynamodb.UpdateItemInput{
+		TableName: dailyDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"daily_date": {
+				S: aws.String(date),
+			},
+		},
+		UpdateExpression: aws.String("SET results = list_append(if_not_exists(results, :empty), :results)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":empty": {
+				L: []*dynamodb.AttributeValue{},
+			},
+			":results": {
+				L: []*dynamodb.AttributeValue{marshalledResult},
+			},
+		},
+	}
+
+	_, err = dailyDao.service.UpdateItem(updateItemInput)
+	return err
+}
+
+// GetDailyChallenge returns the date's challenge, or nil if nobody has played it yet
+func (dailyDao *DailyDao) GetDailyChallenge(date string) (*model.DailyChallenge, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: dailyDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"daily_date": {
+				S: aws.String(date),
+			},
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+
+	output, err := dailyDao.service.GetItem(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	challenge := &model.DailyChallenge{}
+	err = dynamodbattribute.UnmarshalMap(output.Item, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
@@ -0,0 +1,110 @@
+package dao
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/ksanta/word-stallion/model"
+	"strconv"
+	"time"
+)
+
+type FlaggedGameDao struct {
+	service   *dynamodb.DynamoDB
+	tableName *string
+}
+
+func NewFlaggedGameDao(tableName string) *FlaggedGameDao {
+	mySession := session.Must(session.NewSession())
+
+	return &FlaggedGameDao{
+		service:   dynamodb.New(mySession),
+		tableName: aws.String(tableName),
+	}
+}
+
+// flaggedGameLifetime is how long the evidence against a game is kept for review
+const flaggedGameLifetime = 30 * 24 * time.Hour
+
+// AddCheatFlag adds evidence of cheating to the game's record, creating it if this is the first
+func (flaggedGameDao *FlaggedGameDao) AddCheatFlag(gameId string, flag model.CheatFlag) error {
+	marshalledFlag, err := dynamodbattribute.Marshal(flag)
+	if err != nil {
+		return err
+	}
+	updateItemInput := &dynamodb.UpdateItemInput{
+		TableName: flaggedGameDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"game_id": {
+				S: aws.String(gameId),
+			},
+		},
+		UpdateExpression: aws.String("SET flags = list_append(if_not_exists(flags, :empty), :flags), expires_at = :expiresAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":empty": {
+				L: []*dynamodb.AttributeValue{},
+			},
+			":flags": {
+				L: []*dynamodb.AttributeValue{marshalledFlag},
+			},
+			":expiresAt": {
+				N: aws.String(strconv.FormatInt(time.Now().Add(flaggedGameLifetime).Unix(), 10)),
+			},
+		},
+	}
+
+	_, err = flaggedGameDao.service.UpdateItem(updateItemInput)
+	return err
+}
+
+// GetFlaggedGame returns the evidence against the game, or nil if it hasn't been flagged
+func (flaggedGameDao *FlaggedGameDao) GetFlaggedGame(gameId string) (*model.FlaggedGame, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: flaggedGameDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"game_id": {
+				S: aws.String(gameId),
+			},
+		},
+	}
+
+	output, err := flaggedGameDao.service.GetItem(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	flaggedGame := &model.FlaggedGame{}
+	err = dynamodbattribute.UnmarshalMap(output.Item, flaggedGame)
+	if err != nil {
+		return nil, err
+	}
+
+	return flaggedGame, nil
+}
+
+// GetFlaggedGames returns every game that has been flagged and not yet expired
+func (flaggedGameDao *FlaggedGameDao) GetFlaggedGames() ([]model.FlaggedGame, error) {
+	flaggedGames := make([]model.FlaggedGame, 0)
+	scanInput := &dynamodb.ScanInput{
+		TableName: flaggedGameDao.tableName,
+	}
+	var unmarshalErr error
+	err := flaggedGameDao.service.ScanPages(scanInput, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		pageGames := make([]model.FlaggedGame, 0, len(page.Items))
+		unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageGames)
+		flaggedGames = append(flaggedGames, pageGames...)
+		return unmarshalErr == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return flaggedGames, nil
+}
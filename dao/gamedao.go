@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/ksanta/word-stallion/model"
+	"strconv"
 	"time"
 )
 
@@ -25,11 +27,16 @@ func NewGameDao(tableName string) *GameDao {
 	}
 }
 
-func (gameDao *GameDao) GetPendingGame() (*model.Game, error) {
+// GetPendingGame returns the game waiting for players. If there isn't one, a game is created for
+// the player with the connection id, who becomes its creator.
+func (gameDao *GameDao) GetPendingGame(connectionId string) (*model.Game, error) {
 	// Scan for a pending game
+	// Tournament heats are only for the players seeded into them, and daily challenges and
+	// practice games are solo
 	scanInput := &dynamodb.ScanInput{
-		TableName:        gameDao.tableName,
-		FilterExpression: aws.String("game_state = :gameState"),
+		TableName: gameDao.tableName,
+		FilterExpression: aws.String("game_state = :gameState AND attribute_not_exists(tournament_id) " +
+			"AND attribute_not_exists(daily_date) AND attribute_not_exists(practice)"),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
 			":gameState": {
 				S: aws.String(string(model.Pending)),
@@ -47,23 +54,13 @@ func (gameDao *GameDao) GetPendingGame() (*model.Game, error) {
 		gameId := time.Now().String()
 		fmt.Println("Creating a new game:", gameId)
 
-		newGame := &model.Game{
-			GameId:             gameId,
-			GameStartTime:      time.Now().Add(20 * time.Second),
-			TargetScore:        500,
-			OptionsPerQuestion: 3,
-			SecondsPerQuestion: 10,
-			MaxPlayerCount:     6,
-			CorrectAnswer:      -1,
-			GameState:          model.Pending,
-			CreatedAt:          time.Now(),
-			ExpiresAt:          time.Now().Add(10 * time.Minute).Unix(),
-		}
-		err = gameDao.PutGame(newGame)
+		game := newGame(gameId)
+		game.CreatorConnectionId = connectionId
+		err = gameDao.PutGame(game)
 		if err != nil {
 			return nil, err
 		}
-		return newGame, nil
+		return game, nil
 
 	} else if *scanOutput.Count == 1 {
 		// Found a pending game so return that
@@ -91,6 +88,167 @@ func (gameDao *GameDao) PutGame(game *model.Game) error {
 	return err
 }
 
+// PutPendingGame saves a game only if it hasn't started yet. Returns false if the game is no
+// longer pending.
+func (gameDao *GameDao) PutPendingGame(game *model.Game) (bool, error) {
+	marshalledGame, err := dynamodbattribute.MarshalMap(game)
+	if err != nil {
+		return false, err
+	}
+	putItemInput := &dynamodb.PutItemInput{
+		TableName:           gameDao.tableName,
+		Item:                marshalledGame,
+		ConditionExpression: aws.String("game_state = :gameState"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":gameState": {
+				S: aws.String(string(model.Pending)),
+			},
+		},
+	}
+	_, err = gameDao.service.PutItem(putItemInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UseCustomWords flags a pending game as using its own word list. Returns false if the game is
+// no longer pending.
+func (gameDao *GameDao) UseCustomWords(gameId string) (bool, error) {
+	updateItemInput := &dynamodb.UpdateItemInput{
+		TableName: gameDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"game_id": {
+				S: aws.String(gameId),
+			},
+		},
+		UpdateExpression:    aws.String("SET custom_words = :customWords"),
+		ConditionExpression: aws.String("game_state = :gameState"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":customWords": {
+				BOOL: aws.Bool(true),
+			},
+			":gameState": {
+				S: aws.String(string(model.Pending)),
+			},
+		},
+	}
+
+	_, err := gameDao.service.UpdateItem(updateItemInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ClaimFirstCorrect records the player as the first to answer the round correctly. Returns false
+// if another player already has.
+func (gameDao *GameDao) ClaimFirstCorrect(gameId string, roundNumber int, connectionId string) (bool, error) {
+	updateItemInput := &dynamodb.UpdateItemInput{
+		TableName: gameDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"game_id": {
+				S: aws.String(gameId),
+			},
+		},
+		UpdateExpression:    aws.String("SET first_correct_round = :round, first_correct_by = :connectionId"),
+		ConditionExpression: aws.String("attribute_not_exists(first_correct_round) OR first_correct_round < :round"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":round": {
+				N: aws.String(strconv.Itoa(roundNumber)),
+			},
+			":connectionId": {
+				S: aws.String(connectionId),
+			},
+		},
+	}
+
+	_, err := gameDao.service.UpdateItem(updateItemInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// newGame creates a pending game with the default rules
+func newGame(gameId string) *model.Game {
+	return &model.Game{
+		GameId:             gameId,
+		GameStartTime:      time.Now().Add(20 * time.Second),
+		TargetScore:        500,
+		OptionsPerQuestion: 3,
+		SecondsPerQuestion: 10,
+		MaxPlayerCount:     6,
+		CorrectAnswer:      -1,
+		Language:           model.DefaultLanguage,
+		TranslateTo:        model.DefaultLanguage,
+		QuestionModes:      []model.QuestionMode{model.DefinitionMode},
+		ScoringPolicy:      model.ClassicScoring,
+		TeamScoring:        model.SumTeamScoring,
+		Elimination:        model.NoElimination,
+		TieBreaker:         model.FastestTieBreaker,
+		GameState:          model.Pending,
+		Seed:               time.Now().UnixNano(),
+		CreatedAt:          time.Now(),
+		ExpiresAt:          time.Now().Add(10 * time.Minute).Unix(),
+	}
+}
+
+// CreateTournamentGame creates a game for one heat of a tournament. Only the players seeded into
+// the heat can play in it.
+func (gameDao *GameDao) CreateTournamentGame(gameId string, tournamentId string) (*model.Game, error) {
+	game := newGame(gameId)
+	game.TournamentId = tournamentId
+	game.GameStartTime = time.Now()
+	err := gameDao.PutGame(game)
+	if err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// CreateDailyGame creates a solo game for one play of a daily challenge. It asks the challenge's
+// questions using the default rules, so every play of the challenge is the same.
+func (gameDao *GameDao) CreateDailyGame(gameId string, challenge *model.DailyChallenge) (*model.Game, error) {
+	game := newGame(gameId)
+	game.DailyDate = challenge.Date
+	game.Seed = challenge.Seed
+	game.WordsVersion = challenge.WordsVersion
+	game.RoundLimit = model.DailyChallengeRounds
+	game.MaxPlayerCount = 1
+	game.GameStartTime = time.Now()
+	err := gameDao.PutGame(game)
+	if err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// CreatePracticeGame creates a game for one player to practice on their own. Given a player id,
+// the game reviews the words that player has missed before.
+func (gameDao *GameDao) CreatePracticeGame(gameId string, reviewFor string) (*model.Game, error) {
+	game := newGame(gameId)
+	game.Practice = true
+	game.ReviewFor = reviewFor
+	game.RoundLimit = model.PracticeGameRounds
+	game.MaxPlayerCount = 1
+	game.GameStartTime = time.Now()
+	err := gameDao.PutGame(game)
+	if err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
 func (gameDao *GameDao) GetGame(gameId string) (*model.Game, error) {
 	input := &dynamodb.GetItemInput{
 		TableName: gameDao.tableName,
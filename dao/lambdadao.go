@@ -29,6 +29,11 @@ func (functionDao *FunctionDao) InvokeDoRound(functionName string, gameId string
 	return functionDao.invokeGameFunction(functionName, gameId)
 }
 
+// InvokeAdvanceTournament lets the tournament know one of its heats has finished
+func (functionDao *FunctionDao) InvokeAdvanceTournament(functionName string, gameId string) error {
+	return functionDao.invokeGameFunction(functionName, gameId)
+}
+
 func (functionDao *FunctionDao) invokeGameFunction(functionName string, gameId string) error {
 	invokeInput := &lambda.InvokeInput{
 		FunctionName:   aws.String(functionName),
@@ -3,11 +3,13 @@ package dao
 import (
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/ksanta/word-stallion/model"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -27,7 +29,7 @@ func NewPlayerDao(tableName string) *PlayerDao {
 }
 
 // todo: move most of this logic into model.NewPlayer()
-func (playerDao *PlayerDao) AddNewPlayer(connectionId string, gameId string, millisSinceGameCreated int64, name string, icon string) (*model.Player, error) {
+func (playerDao *PlayerDao) AddNewPlayer(connectionId string, gameId string, millisSinceGameCreated int64, name string, icon string, team string, playerId string) (*model.Player, error) {
 	newPlayer := &model.Player{
 		ConnectionId:                     connectionId,
 		GameId:                           gameId,
@@ -36,7 +38,9 @@ func (playerDao *PlayerDao) AddNewPlayer(connectionId string, gameId string, mil
 		Responded:                        false,
 		Name:                             name,
 		Icon:                             icon,
+		PlayerId:                         playerId,
 		Points:                           0,
+		Team:                             team,
 		ExpiresAt:                        time.Now().Add(10 * time.Minute).Unix(),
 	}
 
@@ -63,7 +67,8 @@ func (playerDao *PlayerDao) PutPlayer(player *model.Player) error {
 	return err
 }
 
-func (playerDao *PlayerDao) PutPlayers(players model.Players) error {
+// UpdatePlayers saves the named attributes of each active player. See UpdatePlayer.
+func (playerDao *PlayerDao) UpdatePlayers(players model.Players, attributes ...string) error {
 	waitGroup := sync.WaitGroup{}
 
 	for _, player := range players {
@@ -72,9 +77,9 @@ func (playerDao *PlayerDao) PutPlayers(players model.Players) error {
 			playerCopy := player
 			go func() {
 				defer waitGroup.Done()
-				err := playerDao.PutPlayer(playerCopy)
+				err := playerDao.UpdatePlayer(*playerCopy, attributes...)
 				if err != nil {
-					fmt.Println("error saving player in PutPlayers", err)
+					fmt.Println("error saving player in UpdatePlayers", err)
 				}
 			}()
 		}
@@ -85,6 +90,96 @@ func (playerDao *PlayerDao) PutPlayers(players model.Players) error {
 	return nil
 }
 
+// UpdatePlayer saves only the named attributes of the player, so changes made to the rest of the
+// player at the same time aren't lost. Players that have since left are ignored.
+func (playerDao *PlayerDao) UpdatePlayer(player model.Player, attributes ...string) error {
+	_, err := playerDao.updatePlayer(player, attributes, "attribute_exists(connection_id)", nil)
+	return err
+}
+
+// roundStartAttributes are the attributes of a player changed by starting a round
+var roundStartAttributes = []string{
+	"responded", "streak", "round_points", "response_millis", "round_accuracy", "power_up_earned",
+	"queued_power_ups", "round_power_ups", "option_indexes",
+}
+
+// StartRound saves the player after their round has started, given how many power-ups they had
+// queued when they were read. Returns false if a power-up has been queued since, in which case
+// the player should be fetched and the round started again.
+func (playerDao *PlayerDao) StartRound(player model.Player, queued int) (bool, error) {
+	condition, values := sizeUnchanged("queued_power_ups", ":queued", queued)
+	return playerDao.updatePlayer(player, roundStartAttributes, condition, values)
+}
+
+// SavePowerUpEarned saves the player's power-ups after they earned one, given how many they held
+// when they were read. Returns false if their power-ups have changed since.
+func (playerDao *PlayerDao) SavePowerUpEarned(player model.Player, held int) (bool, error) {
+	condition, values := sizeUnchanged("power_ups", ":held", held)
+	return playerDao.updatePlayer(player, []string{"power_ups"}, condition, values)
+}
+
+// updatePlayer sets the named attributes to the player's values if the condition holds. Returns
+// false if it doesn't.
+func (playerDao *PlayerDao) updatePlayer(player model.Player, attributes []string, condition string, values map[string]*dynamodb.AttributeValue) (bool, error) {
+	marshalledPlayer, err := dynamodbattribute.MarshalMap(player)
+	if err != nil {
+		return false, err
+	}
+
+	names := make(map[string]*string, len(attributes))
+	if values == nil {
+		values = make(map[string]*dynamodb.AttributeValue, len(attributes))
+	}
+	sets := make([]string, len(attributes))
+	for i, attribute := range attributes {
+		name := fmt.Sprintf("#a%d", i)
+		value := fmt.Sprintf(":a%d", i)
+		names[name] = aws.String(attribute)
+		values[value] = marshalledPlayer[attribute]
+		sets[i] = name + " = " + value
+	}
+
+	updateItemInput := &dynamodb.UpdateItemInput{
+		TableName: playerDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"connection_id": {
+				S: aws.String(player.ConnectionId),
+			},
+		},
+		UpdateExpression:          aws.String("SET " + strings.Join(sets, ", ")),
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	}
+
+	_, err = playerDao.service.UpdateItem(updateItemInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sizeUnchanged returns a condition that the list attribute still has the size it was read with,
+// along with the values it uses. Empty lists are saved as null, or not at all.
+func sizeUnchanged(attribute string, placeholder string, size int) (string, map[string]*dynamodb.AttributeValue) {
+	values := map[string]*dynamodb.AttributeValue{
+		placeholder: {
+			N: aws.String(fmt.Sprint(size)),
+		},
+	}
+	condition := fmt.Sprintf("size(%s) = %s", attribute, placeholder)
+	if size == 0 {
+		condition = fmt.Sprintf("attribute_exists(connection_id) AND (attribute_not_exists(%[1]s) OR attribute_type(%[1]s, :null) OR %[2]s)", attribute, condition)
+		values[":null"] = &dynamodb.AttributeValue{
+			S: aws.String("NULL"),
+		}
+	}
+	return condition, values
+}
+
 func (playerDao *PlayerDao) InactivatePlayer(connectionId string) (*model.Player, error) {
 	// Prepare the request
 	updateItemInput := &dynamodb.UpdateItemInput{
@@ -122,6 +217,77 @@ func (playerDao *PlayerDao) InactivatePlayer(connectionId string) (*model.Player
 	return player, nil
 }
 
+// SetQuestionSentAt records when the current question was delivered to the player. Players that
+// have since left are ignored.
+func (playerDao *PlayerDao) SetQuestionSentAt(connectionId string, sentAt time.Time) error {
+	marshalledSentAt, err := dynamodbattribute.Marshal(sentAt)
+	if err != nil {
+		return err
+	}
+	updateItemInput := &dynamodb.UpdateItemInput{
+		TableName: playerDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"connection_id": {
+				S: aws.String(connectionId),
+			},
+		},
+		UpdateExpression:    aws.String("SET question_sent_at = :sentAt"),
+		ConditionExpression: aws.String("attribute_exists(connection_id)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":sentAt": marshalledSentAt,
+		},
+	}
+
+	_, err = playerDao.service.UpdateItem(updateItemInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return nil
+	}
+	return err
+}
+
+// UsePowerUp saves the player's power-ups after they used the one at the index of their
+// inventory, given the player as they were read. Only the power-up attributes are written, so
+// changes made to the rest of the player at the same time aren't lost. Returns false if the
+// player's power-ups have changed since they were read.
+func (playerDao *PlayerDao) UsePowerUp(player model.Player, index int) (bool, error) {
+	powerUp := player.PowerUps[index]
+	queued := len(player.QueuedPowerUps)
+	if !player.UsePowerUp(powerUp) {
+		return false, nil
+	}
+	marshalledQueued, err := dynamodbattribute.Marshal(player.QueuedPowerUps)
+	if err != nil {
+		return false, err
+	}
+
+	// The power-up must still be held, and nothing can have been queued or started since
+	queueUnchanged, values := sizeUnchanged("queued_power_ups", ":queued", queued)
+	values[":powerUp"] = &dynamodb.AttributeValue{
+		S: aws.String(string(powerUp)),
+	}
+	values[":queuedPowerUps"] = marshalledQueued
+	updateItemInput := &dynamodb.UpdateItemInput{
+		TableName: playerDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"connection_id": {
+				S: aws.String(player.ConnectionId),
+			},
+		},
+		UpdateExpression:          aws.String(fmt.Sprintf("REMOVE power_ups[%d] SET queued_power_ups = :queuedPowerUps", index)),
+		ConditionExpression:       aws.String(fmt.Sprintf("power_ups[%d] = :powerUp AND %s", index, queueUnchanged)),
+		ExpressionAttributeValues: values,
+	}
+
+	_, err = playerDao.service.UpdateItem(updateItemInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (playerDao *PlayerDao) GetPlayer(connectionId string) (*model.Player, error) {
 	getItemInput := &dynamodb.GetItemInput{
 		TableName: playerDao.tableName,
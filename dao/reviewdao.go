@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/ksanta/word-stallion/model"
+)
+
+type ReviewDao struct {
+	service   *dynamodb.DynamoDB
+	tableName *string
+}
+
+func NewReviewDao(tableName string) *ReviewDao {
+	mySession := session.Must(session.NewSession())
+
+	return &ReviewDao{
+		service:   dynamodb.New(mySession),
+		tableName: aws.String(tableName),
+	}
+}
+
+// GetReviewDeck returns the player's review deck. A player who hasn't missed any words yet has
+// an empty deck.
+func (reviewDao *ReviewDao) GetReviewDeck(playerId string) (*model.ReviewDeck, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: reviewDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"player_id": {
+				S: aws.String(playerId),
+			},
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+
+	output, err := reviewDao.service.GetItem(input)
+	if err != nil {
+		return nil, err
+	}
+
+	deck := &model.ReviewDeck{
+		PlayerId: playerId,
+		Cards:    make([]model.ReviewCard, 0),
+	}
+	if output.Item == nil {
+		return deck, nil
+	}
+
+	err = dynamodbattribute.UnmarshalMap(output.Item, deck)
+	if err != nil {
+		return nil, err
+	}
+
+	return deck, nil
+}
+
+func (reviewDao *ReviewDao) PutReviewDeck(deck *model.ReviewDeck) error {
+	marshalledDeck, err := dynamodbattribute.MarshalMap(deck)
+	if err != nil {
+		return err
+	}
+	putItemInput := &dynamodb.PutItemInput{
+		TableName: reviewDao.tableName,
+		Item:      marshalledDeck,
+	}
+	_, err = reviewDao.service.PutItem(putItemInput)
+	return err
+}
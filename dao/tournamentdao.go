@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/ksanta/word-stallion/model"
+	"strconv"
+	"time"
+)
+
+type TournamentDao struct {
+	service   *dynamodb.DynamoDB
+	tableName *string
+}
+
+func NewTournamentDao(tableName string) *TournamentDao {
+	mySession := session.Must(session.NewSession())
+
+	return &TournamentDao{
+		service:   dynamodb.New(mySession),
+		tableName: aws.String(tableName),
+	}
+}
+
+// tournamentLifetime is how long a tournament is kept after it was last updated
+const tournamentLifetime = 24 * time.Hour
+
+// PutTournament saves the tournament if nobody else has saved it since it was fetched. Returns
+// false if someone has, in which case the tournament should be fetched and updated again.
+func (tournamentDao *TournamentDao) PutTournament(tournament *model.Tournament) (bool, error) {
+	previousVersion := tournament.Version
+	tournament.Version++
+	tournament.ExpiresAt = time.Now().Add(tournamentLifetime).Unix()
+
+	marshalledTournament, err := dynamodbattribute.MarshalMap(tournament)
+	if err != nil {
+		return false, err
+	}
+	putItemInput := &dynamodb.PutItemInput{
+		TableName:           tournamentDao.tableName,
+		Item:                marshalledTournament,
+		ConditionExpression: aws.String("attribute_not_exists(tournament_id) OR version = :version"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":version": {
+				N: aws.String(strconv.Itoa(previousVersion)),
+			},
+		},
+	}
+
+	_, err = tournamentDao.service.PutItem(putItemInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		tournament.Version = previousVersion
+		return false, nil
+	}
+	if err != nil {
+		tournament.Version = previousVersion
+		return false, err
+	}
+	return true, nil
+}
+
+// GetTournament returns the tournament, or nil if there isn't one with the id
+func (tournamentDao *TournamentDao) GetTournament(tournamentId string) (*model.Tournament, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: tournamentDao.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"tournament_id": {
+				S: aws.String(tournamentId),
+			},
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+
+	output, err := tournamentDao.service.GetItem(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Item == nil {
+		return nil, nil
+	}
+
+	tournament := &model.Tournament{}
+	err = dynamodbattribute.UnmarshalMap(output.Item, tournament)
+	if err != nil {
+		return nil, err
+	}
+
+	return tournament, nil
+}
@@ -1,68 +1,237 @@
 package dao
 
 import (
+	"bufio"
 	"bytes"
-	"encoding/csv"
+	"compress/gzip"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/ksanta/word-stallion/model"
 	"io"
-	"log"
+	"sort"
+	"strings"
+	"time"
 )
 
 type WordsDao struct {
 	bucketName      string
+	service         *s3.S3
 	downloadService *s3manager.Downloader
 	uploadService   *s3manager.Uploader
+	// How words are saved. Words are read in whichever format they were saved in.
+	format   model.WordListFormat
+	compress bool
 }
 
+// KEY is where the corpus was kept before it was versioned
 const KEY = "words.txt"
 
+// gameWordsPrefix is where word lists uploaded for a single game are kept
+const gameWordsPrefix = "games/"
+
+// versionsPrefix is where each snapshot of the corpus is kept
+const versionsPrefix = "versions/"
+
+// currentVersionKey holds the version of the corpus that new games will use
+const currentVersionKey = "current.txt"
+
+// LegacyVersion refers to the unversioned corpus, used until the first versioned save
+const LegacyVersion = "legacy"
+
+// versionFormat is a timestamp so versions sort by the time they were saved. The fraction of a
+// second keeps versions saved in the same second apart.
+const versionFormat = "20060102T150405.000000000Z"
+
+// textExtension ends the keys of versions saved before versions had a fraction of a second. Keys
+// no longer have an extension, as words can be saved in any format and are read in whichever
+// format they were saved in.
+const textExtension = ".txt"
+
 func NewWordsDao(bucketName string) *WordsDao {
 	mySession := session.Must(session.NewSession())
 
 	return &WordsDao{
 		bucketName:      bucketName,
+		service:         s3.New(mySession),
 		downloadService: s3manager.NewDownloader(mySession),
 		uploadService:   s3manager.NewUploader(mySession),
+		format:          model.CSVFormat,
+		compress:        false,
+	}
+}
+
+// SetStorageFormat changes how words are saved from uncompressed CSV. Only CSV and JSON Lines
+// can be saved.
+func (wordsDao *WordsDao) SetStorageFormat(format model.WordListFormat, compress bool) error {
+	if !format.IsWritable() {
+		return fmt.Errorf("words can't be saved as %s", format)
+	}
+	wordsDao.format = format
+	wordsDao.compress = compress
+	return nil
+}
+
+// SaveWords saves a new version of the corpus and makes it the current version
+func (wordsDao *WordsDao) SaveWords(words model.Words) (string, error) {
+	version := time.Now().UTC().Format(versionFormat)
+	err := wordsDao.saveWordsToKey(words, versionKey(version))
+	if err != nil {
+		return "", err
 	}
+
+	return version, wordsDao.putCurrentVersion(version)
+}
+
+// GetWords gets the current version of the corpus
+func (wordsDao *WordsDao) GetWords() (model.Words, error) {
+	version, err := wordsDao.GetCurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	return wordsDao.GetWordsVersion(version)
+}
+
+// GetWordsVersion gets one version of the corpus
+func (wordsDao *WordsDao) GetWordsVersion(version string) (model.Words, error) {
+	return wordsDao.getWordsFromKey(versionKey(version))
 }
 
-func (wordsDao *WordsDao) SaveWords(words model.Words) error {
+// GetCurrentVersion returns the version of the corpus that new games will use
+func (wordsDao *WordsDao) GetCurrentVersion() (string, error) {
+	buf := aws.NewWriteAtBuffer([]byte{})
+
+	getObjectInput := &s3.GetObjectInput{
+		Bucket: aws.String(wordsDao.bucketName),
+		Key:    aws.String(currentVersionKey),
+	}
+
+	_, err := wordsDao.downloadService.Download(buf, getObjectInput)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return LegacyVersion, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf.Bytes())), nil
+}
+
+// ListVersions returns all versions of the corpus, newest first
+func (wordsDao *WordsDao) ListVersions() ([]string, error) {
+	versions := make([]string, 0)
+
+	listObjectsInput := &s3.ListObjectsV2Input{
+		Bucket: aws.String(wordsDao.bucketName),
+		Prefix: aws.String(versionsPrefix),
+	}
+	err := wordsDao.service.ListObjectsV2Pages(listObjectsInput, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			version := strings.TrimSuffix(strings.TrimPrefix(*object.Key, versionsPrefix), textExtension)
+			versions = append(versions, version)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+	return versions, nil
+}
+
+// RollbackTo makes an earlier version of the corpus the current version
+func (wordsDao *WordsDao) RollbackTo(version string) error {
+	headObjectInput := &s3.HeadObjectInput{
+		Bucket: aws.String(wordsDao.bucketName),
+		Key:    aws.String(versionKey(version)),
+	}
+	_, err := wordsDao.service.HeadObject(headObjectInput)
+	if err != nil {
+		return fmt.Errorf("error finding version %s: %w", version, err)
+	}
+
+	return wordsDao.putCurrentVersion(version)
+}
+
+func (wordsDao *WordsDao) putCurrentVersion(version string) error {
+	putObjectInput := &s3manager.UploadInput{
+		Body:   strings.NewReader(version),
+		Bucket: aws.String(wordsDao.bucketName),
+		Key:    aws.String(currentVersionKey),
+	}
+
+	_, err := wordsDao.uploadService.Upload(putObjectInput)
+	return err
+}
+
+func versionKey(version string) string {
+	switch {
+	case version == LegacyVersion:
+		return KEY
+	case !strings.Contains(version, "."):
+		// Saved to the second, when keys had an extension
+		return versionsPrefix + version + textExtension
+	default:
+		return versionsPrefix + version
+	}
+}
+
+// SaveGameWords saves a word list to be used exclusively by one game
+func (wordsDao *WordsDao) SaveGameWords(gameId string, words model.Words) error {
+	return wordsDao.saveWordsToKey(words, gameWordsKey(gameId))
+}
+
+// GetGameWords gets the word list uploaded for one game
+func (wordsDao *WordsDao) GetGameWords(gameId string) (model.Words, error) {
+	return wordsDao.getWordsFromKey(gameWordsKey(gameId))
+}
+
+func gameWordsKey(gameId string) string {
+	return gameWordsPrefix + gameId + "/words"
+}
+
+func (wordsDao *WordsDao) saveWordsToKey(words model.Words, key string) error {
 	// buffer collects the bytes
 	buffer := &bytes.Buffer{}
-	// csvWriter writes bytes in CSV format
-	csvWriter := csv.NewWriter(buffer)
+	var writer io.Writer = buffer
+	var gzipWriter *gzip.Writer
+	if wordsDao.compress {
+		gzipWriter = gzip.NewWriter(buffer)
+		writer = gzipWriter
+	}
 
 	// Stream these into a byte array
-	for _, word := range words {
-		err := csvWriter.Write(word.ToStringSlice())
+	err := model.WriteWords(wordsDao.format, writer, words)
+	if err != nil {
+		return err
+	}
+	if gzipWriter != nil {
+		err = gzipWriter.Close()
 		if err != nil {
-			return fmt.Errorf("error writing csv: %w", err)
+			return fmt.Errorf("error compressing words: %w", err)
 		}
 	}
-	csvWriter.Flush()
 
 	// Save the byte array to S3
 	putObjectInput := &s3manager.UploadInput{
 		Body:   buffer,
 		Bucket: aws.String(wordsDao.bucketName),
-		Key:    aws.String(KEY),
+		Key:    aws.String(key),
 	}
 
-	_, err := wordsDao.uploadService.Upload(putObjectInput)
+	_, err = wordsDao.uploadService.Upload(putObjectInput)
 	return err
 }
 
-func (wordsDao *WordsDao) GetWords() (model.Words, error) {
+func (wordsDao *WordsDao) getWordsFromKey(key string) (model.Words, error) {
 	buf := aws.NewWriteAtBuffer([]byte{})
 
 	getObjectInput := &s3.GetObjectInput{
 		Bucket: aws.String(wordsDao.bucketName),
-		Key:    aws.String(KEY),
+		Key:    aws.String(key),
 	}
 
 	_, err := wordsDao.downloadService.Download(buf, getObjectInput)
@@ -70,18 +239,44 @@ func (wordsDao *WordsDao) GetWords() (model.Words, error) {
 		return nil, err
 	}
 
-	words := model.Words{}
-	wordReader := csv.NewReader(bytes.NewBuffer(buf.Bytes()))
-	for {
-		record, err := wordReader.Read()
-		if err == io.EOF {
-			break
-		}
+	// Words may have been saved in any format, with or without compression
+	var reader io.Reader = bytes.NewReader(buf.Bytes())
+	if bytes.HasPrefix(buf.Bytes(), gzipMagicNumber) {
+		reader, err = gzip.NewReader(reader)
 		if err != nil {
-			log.Fatal(err)
+			return nil, fmt.Errorf("error decompressing %s: %w", key, err)
 		}
-		word := model.NewWord(record)
-		words = append(words, word)
 	}
+	bufferedReader := bufio.NewReader(reader)
+	// Peek errors are ignored as a short word list has less to peek at
+	start, _ := bufferedReader.Peek(formatDetectionLength)
+	format := model.DetectWordListFormat(string(start))
+
+	// Skip bad rows rather than failing, so one bad word can't stop every game
+	words, wordErrors := model.ReadWords(format, bufferedReader)
+	logWordErrors(key, wordErrors)
 	return words, nil
 }
+
+// gzipMagicNumber is the start of all gzip compressed content
+var gzipMagicNumber = []byte{0x1f, 0x8b}
+
+// formatDetectionLength is how much of the words is looked at to detect the format
+const formatDetectionLength = 512
+
+// maxWordErrorsLogged limits how many bad rows are logged when a word list is loaded
+const maxWordErrorsLogged = 10
+
+func logWordErrors(key string, wordErrors model.WordErrors) {
+	if len(wordErrors) == 0 {
+		return
+	}
+	fmt.Println("Skipped", len(wordErrors), "invalid words in", key)
+	for i, wordError := range wordErrors {
+		if i == maxWordErrorsLogged {
+			fmt.Println("...")
+			break
+		}
+		fmt.Println(" ", wordError)
+	}
+}
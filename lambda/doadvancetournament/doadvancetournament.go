@@ -0,0 +1,78 @@
+// Advances the top players of a finished tournament heat, and starts the next round once every
+// heat in the round has finished
+package main
+
+import (
+	"fmt"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ksanta/word-stallion/dao"
+	"github.com/ksanta/word-stallion/model"
+	"github.com/ksanta/word-stallion/service"
+	"os"
+)
+
+var (
+	gameDao           *dao.GameDao
+	playerDao         *dao.PlayerDao
+	tournamentService *service.TournamentService
+)
+
+func init() {
+	gameDao = dao.NewGameDao(os.Getenv("GAMES_TABLE"))
+	playerDao = dao.NewPlayerDao(os.Getenv("PLAYERS_TABLE"))
+	tournamentService = service.NewTournamentService(
+		dao.NewTournamentDao(os.Getenv("TOURNAMENTS_TABLE")),
+		gameDao,
+		playerDao,
+		dao.NewApiDao(os.Getenv("API_ENDPOINT")),
+		dao.NewFunctionDao(),
+		os.Getenv("DO_START_GAME_FUNCTION_NAME"))
+}
+
+func handler(gameId string) error {
+	fmt.Println("Getting game")
+	game, err := gameDao.GetGame(gameId)
+	if err != nil {
+		return fmt.Errorf("error getting game: %w", err)
+	}
+	if game == nil || game.TournamentId == "" || game.GameState != model.Finished {
+		fmt.Println("Game is not a finished tournament heat - ignoring")
+		return nil
+	}
+
+	fmt.Println("Getting players")
+	players, err := playerDao.GetPlayers(gameId)
+	if err != nil {
+		return fmt.Errorf("error getting players: %w", err)
+	}
+	finishingOrder := players.FinishingOrder(game.TieBreaker)
+
+	recorded := false
+	tournament, err := tournamentService.UpdateTournament(game.TournamentId, func(tournament *model.Tournament) error {
+		// Players who have gone can't be seeded into the next round
+		err := tournamentService.WithdrawDisconnected(tournament)
+		if err != nil {
+			return err
+		}
+		recorded = tournament.RecordHeatResult(gameId, finishingOrder)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error recording heat result: %w", err)
+	}
+	if !recorded {
+		fmt.Println("Heat", gameId, "was already recorded - ignoring")
+		return nil
+	}
+
+	err = tournamentService.StartHeats(tournament)
+	if err != nil {
+		return err
+	}
+	tournamentService.SendBracketToParticipants(tournament)
+	return nil
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,61 @@
+// Lists the games flagged for cheating and the evidence against them. Invoked directly by an
+// administrator.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ksanta/word-stallion/dao"
+	"github.com/ksanta/word-stallion/model"
+	"os"
+)
+
+var (
+	flaggedGameDao *dao.FlaggedGameDao
+)
+
+// Request is the event this function is invoked with
+type Request struct {
+	// One of "list" or "get"
+	Action string
+	// The game to get
+	GameId string
+}
+
+// Response is returned to the invoker
+type Response struct {
+	FlaggedGames []model.FlaggedGame
+}
+
+func init() {
+	flaggedGameDao = dao.NewFlaggedGameDao(os.Getenv("FLAGGED_GAMES_TABLE"))
+}
+
+func handler(request Request) (*Response, error) {
+	switch request.Action {
+	case "list":
+		flaggedGames, err := flaggedGameDao.GetFlaggedGames()
+		if err != nil {
+			return nil, fmt.Errorf("error getting flagged games: %w", err)
+		}
+		fmt.Println("Found", len(flaggedGames), "flagged games")
+		return &Response{FlaggedGames: flaggedGames}, nil
+
+	case "get":
+		flaggedGame, err := flaggedGameDao.GetFlaggedGame(request.GameId)
+		if err != nil {
+			return nil, fmt.Errorf("error getting flagged game: %w", err)
+		}
+		if flaggedGame == nil {
+			return nil, errors.New("game has not been flagged")
+		}
+		return &Response{FlaggedGames: []model.FlaggedGame{*flaggedGame}}, nil
+	}
+
+	return nil, fmt.Errorf("unknown action: %s", request.Action)
+}
+
+func main() {
+	lambda.Start(handler)
+}
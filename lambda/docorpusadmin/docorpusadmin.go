@@ -0,0 +1,97 @@
+// Lists, compares and rolls back versions of the word corpus. Invoked directly by an administrator.
+package main
+
+import (
+	"fmt"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ksanta/word-stallion/dao"
+	"github.com/ksanta/word-stallion/model"
+	"os"
+)
+
+var (
+	wordsDao *dao.WordsDao
+)
+
+// Request is the event this function is invoked with
+type Request struct {
+	// One of "list", "diff" or "rollback"
+	Action string
+	// The version to roll back to
+	Version string
+	// The versions to compare. Defaults to the current version if not provided.
+	From string
+	To   string
+}
+
+// Response is returned to the invoker
+type Response struct {
+	CurrentVersion string
+	Versions       []string         `json:",omitempty"`
+	Diff           *model.WordsDiff `json:",omitempty"`
+}
+
+func init() {
+	wordsDao = dao.NewWordsDao(os.Getenv("WORDS_BUCKET"))
+}
+
+func handler(request Request) (*Response, error) {
+	currentVersion, err := wordsDao.GetCurrentVersion()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current version: %w", err)
+	}
+	response := &Response{
+		CurrentVersion: currentVersion,
+	}
+
+	switch request.Action {
+	case "list":
+		response.Versions, err = wordsDao.ListVersions()
+		if err != nil {
+			return nil, fmt.Errorf("error listing versions: %w", err)
+		}
+
+	case "diff":
+		from, err := getVersion(request.From, currentVersion)
+		if err != nil {
+			return nil, err
+		}
+		to, err := getVersion(request.To, currentVersion)
+		if err != nil {
+			return nil, err
+		}
+		diff := model.DiffWords(from, to)
+		response.Diff = &diff
+
+	case "rollback":
+		if request.Version == "" {
+			return nil, fmt.Errorf("a version to roll back to is required")
+		}
+		fmt.Println("Rolling back from", currentVersion, "to", request.Version)
+		err = wordsDao.RollbackTo(request.Version)
+		if err != nil {
+			return nil, fmt.Errorf("error rolling back: %w", err)
+		}
+		response.CurrentVersion = request.Version
+
+	default:
+		return nil, fmt.Errorf("unknown action: %s", request.Action)
+	}
+
+	return response, nil
+}
+
+func getVersion(version string, currentVersion string) (model.Words, error) {
+	if version == "" {
+		version = currentVersion
+	}
+	words, err := wordsDao.GetWordsVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("error getting version %s: %w", version, err)
+	}
+	return words, nil
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -14,27 +14,41 @@ import (
 var (
 	gameDao       *dao.GameDao
 	playerDao     *dao.PlayerDao
+	wordsDao      *dao.WordsDao
+	reviewDao     *dao.ReviewDao
 	playerService *service.PlayerService
-	wordsByType   map[string]model.Words
+	// Each version of the corpus used by a game, keyed by version
+	wordsByVersion = make(map[string]model.Words)
+	// Word lists uploaded for individual games, keyed by game id. Each is evicted once its game
+	// expires, so lists don't pile up in a warm container.
+	customWordsByGame = make(map[string]customWords)
 )
 
+// customWords is a word list uploaded for a game, kept until the game expires
+type customWords struct {
+	words     model.Words
+	expiresAt int64
+}
+
 func init() {
 	gameDao = dao.NewGameDao(os.Getenv("GAMES_TABLE"))
 	playerDao = dao.NewPlayerDao(os.Getenv("PLAYERS_TABLE"))
+	reviewDao = dao.NewReviewDao(os.Getenv("REVIEW_DECKS_TABLE"))
 	apiDao := dao.NewApiDao(os.Getenv("API_ENDPOINT"))
 	playerService = service.NewPlayerService(playerDao, apiDao)
 
 	bucketName := os.Getenv("WORDS_BUCKET")
-	wordsDao := dao.NewWordsDao(bucketName)
-	words, err := wordsDao.GetWords()
+	wordsDao = dao.NewWordsDao(bucketName)
+
+	// Preload the current version, as that is what new games will be pinned to. A failure here
+	// is retried by the handler.
+	version, err := wordsDao.GetCurrentVersion()
+	if err == nil {
+		_, err = getWordsVersion(version)
+	}
 	if err != nil {
 		fmt.Println("error loading words:", err)
-		return
 	}
-	fmt.Println("Init: loaded", len(words), "words")
-	wordsByType = words.GroupByType()
-
-	rand.Seed(time.Now().Unix())
 }
 
 func handler(gameId string) error {
@@ -52,10 +66,34 @@ func handler(gameId string) error {
 
 	// Prepare question and answer
 	fmt.Println("Preparing a new question")
-	wordType := model.PickRandomType()
-	wordsInThisRound := wordsByType[wordType].PickRandomWords(game.OptionsPerQuestion)
-	game.CorrectAnswer = wordsInThisRound.PickRandomIndex()
+	words, err := getGameWords(game)
+	if err != nil {
+		return fmt.Errorf("error getting words: %w\n", err)
+	}
+	game.RoundNumber++
+	// Questions are picked from the game's seed, so daily challenges ask everyone the same ones
+	rng := game.RoundRand()
+	game.RoundMode = game.PickRandomQuestionMode(rng)
+	// Review games ask the words the player is due to review
+	selectWord := randomWord
+	if game.IsReview() {
+		deck, err := reviewDao.GetReviewDeck(game.ReviewFor)
+		if err != nil {
+			return fmt.Errorf("error getting review deck: %w\n", err)
+		}
+		selectWord = dueWord(game, deck)
+	}
+	question, err := prepareQuestion(rng, game, words, selectWord)
+	if err != nil {
+		return fmt.Errorf("error preparing question: %w\n", err)
+	}
+	game.CorrectAnswer = question.CorrectAnswer
+	game.CorrectWord = question.CorrectWord
+	game.RoundWord = question.Word.Word
 	game.RoundStartTime = time.Now()
+	if game.IsPractice() {
+		game.AddPracticeRound(question)
+	}
 	fmt.Println("Updating game")
 	err = gameDao.PutGame(game)
 	if err != nil {
@@ -63,14 +101,30 @@ func handler(gameId string) error {
 	}
 
 	fmt.Println("Updating players to waiting")
+	players.ResetStreaksOfNonResponders()
 	players.SetActivesToNotResponded()
-	err = playerDao.PutPlayers(players)
+	// Power-ups and shuffling change the question each player sees. Each player's view is their
+	// own, so it isn't picked from the game's seed.
+	personalRand := rand.New(rand.NewSource(time.Now().UnixNano()))
+	questions := make(map[string]model.Question, len(players))
+	watching := make(model.Players, 0, len(players))
+	for _, player := range players {
+		if !player.IsRacing() {
+			watching = append(watching, player)
+			continue
+		}
+		questions[player.ConnectionId], err = startRound(personalRand, player, question, game.ShuffleOptions)
+		if err != nil {
+			return fmt.Errorf("error saving player: %w\n", err)
+		}
+	}
+	err = playerDao.UpdatePlayers(watching, "responded", "streak")
 	if err != nil {
 		return fmt.Errorf("error saving players: %w\n", err)
 	}
 
 	fmt.Println("Sending question to all players")
-	err = playerService.SendQuestionToActivePlayers(players, wordsInThisRound, game.CorrectAnswer, game.SecondsPerQuestion)
+	err = playerService.SendQuestionToActivePlayers(*game, players, questions)
 	if err != nil {
 		return fmt.Errorf("error sending msg to players: %w\n", err)
 	}
@@ -78,6 +132,243 @@ func handler(gameId string) error {
 	return nil
 }
 
+// maxPowerUpAttempts limits how many times starting a player's round is retried when they queue
+// a power-up at the same time
+const maxPowerUpAttempts = 3
+
+// startRound starts the round for the player and saves them, returning the question as they see
+// it. Power-ups they queue at the same time are put into effect rather than lost.
+func startRound(rng *rand.Rand, player *model.Player, question model.Question, shuffle bool) (model.Question, error) {
+	for attempt := 0; ; attempt++ {
+		started := *player
+		personal := started.StartRound(rng, question, shuffle)
+		saved, err := playerDao.StartRound(started, len(player.QueuedPowerUps))
+		if err != nil {
+			return model.Question{}, err
+		}
+		if saved {
+			*player = started
+			return personal, nil
+		}
+		if attempt+1 >= maxPowerUpAttempts {
+			return model.Question{}, fmt.Errorf("gave up after %d attempts", maxPowerUpAttempts)
+		}
+
+		fmt.Println("Power-ups were changed by someone else - retrying")
+		current, err := playerDao.GetPlayer(player.ConnectionId)
+		if err != nil {
+			return model.Question{}, err
+		}
+		if current == nil {
+			return personal, nil
+		}
+		player.PowerUps = current.PowerUps
+		player.QueuedPowerUps = current.QueuedPowerUps
+	}
+}
+
+// getGameWords returns the words a game can use. This is the game's own word list if one was
+// uploaded, otherwise the game's version of the corpus in the game's language.
+func getGameWords(game *model.Game) (model.Words, error) {
+	if game.CustomWords {
+		return getCustomWords(game.GameId, game.ExpiresAt)
+	}
+
+	// Pin the game to the current version of the corpus, so it can't change mid-game
+	if game.WordsVersion == "" {
+		version, err := wordsDao.GetCurrentVersion()
+		if err != nil {
+			return nil, err
+		}
+		game.WordsVersion = version
+		fmt.Println("Pinning game to words version", game.WordsVersion)
+	}
+
+	words, err := getWordsVersion(game.WordsVersion)
+	if err != nil {
+		return nil, err
+	}
+	// Games created before languages were introduced are in the default language
+	language := game.Language
+	if language == "" {
+		language = model.DefaultLanguage
+	}
+	return words.FilterByLanguage(language), nil
+}
+
+// wordSelector picks the word a question is about from the candidates. Returns false to leave
+// the pick to chance.
+type wordSelector func(candidates model.Words) (model.Word, bool)
+
+// randomWord leaves every pick to chance
+func randomWord(model.Words) (model.Word, bool) {
+	return model.Word{}, false
+}
+
+// dueWord picks the most overdue word in the deck that hasn't been asked yet this game
+func dueWord(game *model.Game, deck *model.ReviewDeck) wordSelector {
+	due := deck.DueCards(game.Language, time.Now())
+	return func(candidates model.Words) (model.Word, bool) {
+		for _, card := range due {
+			if game.AskedInPractice(card.Word) {
+				continue
+			}
+			for _, word := range candidates {
+				if word.Word == card.Word {
+					return word, true
+				}
+			}
+		}
+		return model.Word{}, false
+	}
+}
+
+// prepareQuestion picks words of the same type and makes a question of the round's kind
+func prepareQuestion(rng *rand.Rand, game *model.Game, words model.Words, selectWord wordSelector) (model.Question, error) {
+	switch game.RoundMode {
+	case model.TranslationMode:
+		words = words.FilterByTranslation(game.TranslateTo)
+	case model.PartOfSpeechMode:
+		// Only words of the main parts of speech can be offered as options
+		words = words.FilterByTypes(model.WordTypes)
+		if len(words) == 0 {
+			return model.Question{}, fmt.Errorf("not enough words for a %s question", game.RoundMode)
+		}
+		word, chosen := selectWord(words)
+		if !chosen {
+			word = words[words.PickRandomIndex(rng)]
+		}
+		return model.NewPartOfSpeechQuestion(rng, word, game.OptionsPerQuestion, game.Language, game.PartOfSpeechHint), nil
+	case model.FillInBlankMode:
+		return prepareFillInBlankQuestion(rng, game, words, selectWord)
+	case model.SynonymMode, model.AntonymMode:
+		return prepareRelatedWordQuestion(rng, game, words, selectWord)
+	}
+
+	wordsByType := words.GroupByType()
+	var wordsInThisRound model.Words
+	var correctAnswer int
+	playableWords := words.FilterByTypes(model.PlayableTypes(wordsByType, game.OptionsPerQuestion))
+	if word, chosen := selectWord(playableWords); chosen {
+		otherWords := wordsByType[word.WordType].Without(word).PickRandomWords(rng, game.OptionsPerQuestion-1)
+		wordsInThisRound, correctAnswer = otherWords.WithWordAtRandom(rng, word)
+	} else {
+		wordType := model.PickRandomTypeFrom(rng, wordsByType, game.OptionsPerQuestion)
+		if wordType == "" {
+			return model.Question{}, fmt.Errorf("not enough words for a %s question", game.RoundMode)
+		}
+		wordsInThisRound = wordsByType[wordType].PickRandomWords(rng, game.OptionsPerQuestion)
+		correctAnswer = wordsInThisRound.PickRandomIndex(rng)
+	}
+
+	switch game.RoundMode {
+	case model.TranslationMode:
+		return model.NewTranslationQuestion(wordsInThisRound, correctAnswer, game.Language, game.TranslateTo), nil
+	case model.TypeInMode:
+		return model.NewTypeInQuestion(wordsInThisRound[correctAnswer], game.Language), nil
+	default:
+		return model.NewDefinitionQuestion(wordsInThisRound, correctAnswer, game.Language), nil
+	}
+}
+
+// maxFillInBlankAttempts limits how many words are tried when looking for an example sentence
+// that the word can be blanked out of
+const maxFillInBlankAttempts = 20
+
+// prepareFillInBlankQuestion picks a word with an example sentence, and other words of the same
+// type as the options. Only the first word tried is left to the selector.
+func prepareFillInBlankQuestion(rng *rand.Rand, game *model.Game, words model.Words, selectWord wordSelector) (model.Question, error) {
+	wordsWithExamples := words.FilterByExamples()
+	wordsByType := words.GroupByType()
+
+	for attempt := 0; attempt < maxFillInBlankAttempts && len(wordsWithExamples) > 0; attempt++ {
+		word, chosen := model.Word{}, false
+		if attempt == 0 {
+			word, chosen = selectWord(wordsWithExamples)
+		}
+		if !chosen {
+			word = wordsWithExamples[wordsWithExamples.PickRandomIndex(rng)]
+		}
+		distractors := wordsByType[word.WordType].Without(word).PickRandomWords(rng, game.OptionsPerQuestion-1)
+		if len(distractors) < game.OptionsPerQuestion-1 {
+			continue
+		}
+
+		question, blanked := model.NewFillInBlankQuestion(rng, word, distractors, game.Language)
+		if blanked {
+			return question, nil
+		}
+	}
+	return model.Question{}, fmt.Errorf("not enough words for a %s question", game.RoundMode)
+}
+
+// maxRelatedWordAttempts limits how many words are tried when looking for one with enough
+// unrelated words of the same type to offer as the other options
+const maxRelatedWordAttempts = 20
+
+// prepareRelatedWordQuestion picks a word with synonyms or antonyms, and unrelated words of the
+// same type as the other options. Only the first word tried is left to the selector.
+func prepareRelatedWordQuestion(rng *rand.Rand, game *model.Game, words model.Words, selectWord wordSelector) (model.Question, error) {
+	wordsWithRelatedWords := words.FilterByRelatedWords(game.RoundMode)
+
+	for attempt := 0; attempt < maxRelatedWordAttempts && len(wordsWithRelatedWords) > 0; attempt++ {
+		word, chosen := model.Word{}, false
+		if attempt == 0 {
+			word, chosen = selectWord(wordsWithRelatedWords)
+		}
+		if !chosen {
+			word = wordsWithRelatedWords[wordsWithRelatedWords.PickRandomIndex(rng)]
+		}
+		distractors := words.FilterByTypes([]string{word.WordType}).UnrelatedTo(word).PickRandomWords(rng, game.OptionsPerQuestion-1)
+		if len(distractors) < game.OptionsPerQuestion-1 {
+			continue
+		}
+
+		return model.NewRelatedWordQuestion(rng, game.RoundMode, word, distractors, game.Language), nil
+	}
+	return model.Question{}, fmt.Errorf("not enough words for a %s question", game.RoundMode)
+}
+
+// getWordsVersion returns a version of the corpus, loading it on first use
+func getWordsVersion(version string) (model.Words, error) {
+	if words, present := wordsByVersion[version]; present {
+		return words, nil
+	}
+
+	words, err := wordsDao.GetWordsVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("Loaded", len(words), "words from version", version)
+	wordsByVersion[version] = words
+	return words, nil
+}
+
+// getCustomWords returns the word list uploaded for a game, loading it on first use. Lists of
+// games that have expired are evicted.
+func getCustomWords(gameId string, expiresAt int64) (model.Words, error) {
+	now := time.Now().Unix()
+	for cachedGameId, cached := range customWordsByGame {
+		if cached.expiresAt <= now {
+			delete(customWordsByGame, cachedGameId)
+		}
+	}
+	if cached, present := customWordsByGame[gameId]; present {
+		return cached.words, nil
+	}
+
+	words, err := wordsDao.GetGameWords(gameId)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("Loaded", len(words), "words for game", gameId)
+	customWordsByGame[gameId] = customWords{
+		words:     words,
+		expiresAt: expiresAt,
+	}
+	return words, nil
+}
+
 func main() {
 	lambda.Start(handler)
 }
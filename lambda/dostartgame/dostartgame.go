@@ -41,14 +41,18 @@ func handler(gameId string) error {
 
 	// Update game to in progress
 	game.GameState = model.InProgress
+	game.StartedAt = time.Now()
 	game.ExpiresAt = time.Now().Add(10 * time.Minute).Unix()
 	err = gameDao.PutGame(game)
 	if err != nil {
 		return fmt.Errorf("error updating game to in progress: %w", err)
 	}
 
-	// Send "about to start" message to all active players
-	const startingInSeconds = 5
+	// Send "about to start" message to all active players. Practice games start straight away.
+	startingInSeconds := 5
+	if game.IsPractice() {
+		startingInSeconds = 0
+	}
 	_, err = playerService.SendAboutToStartToActivePlayers(game.GameId, startingInSeconds)
 	if err != nil {
 		return fmt.Errorf("error sending msg to all players: %w", err)
@@ -56,7 +60,7 @@ func handler(gameId string) error {
 
 	// Sleep for a bit
 	fmt.Println("Sleeping for", startingInSeconds)
-	time.Sleep(startingInSeconds * time.Second)
+	time.Sleep(time.Duration(startingInSeconds) * time.Second)
 
 	// Asynchronously invoke DoRound function
 	fmt.Println("Invoking function", doRoundFunctionName)
@@ -0,0 +1,97 @@
+// Creates, starts and shows tournaments. Invoked directly by an administrator.
+package main
+
+import (
+	"fmt"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ksanta/word-stallion/dao"
+	"github.com/ksanta/word-stallion/model"
+	"github.com/ksanta/word-stallion/service"
+	"os"
+	"time"
+)
+
+var (
+	tournamentDao     *dao.TournamentDao
+	tournamentService *service.TournamentService
+)
+
+// Request is the event this function is invoked with
+type Request struct {
+	// One of "create", "start" or "get"
+	Action string
+	// The tournament to start or get
+	TournamentId string
+	// The tournament to create
+	Name           string
+	HeatSize       int
+	AdvancePerHeat int
+}
+
+// Response is returned to the invoker
+type Response struct {
+	Tournament *model.Tournament
+}
+
+func init() {
+	tournamentDao = dao.NewTournamentDao(os.Getenv("TOURNAMENTS_TABLE"))
+	tournamentService = service.NewTournamentService(
+		tournamentDao,
+		dao.NewGameDao(os.Getenv("GAMES_TABLE")),
+		dao.NewPlayerDao(os.Getenv("PLAYERS_TABLE")),
+		dao.NewApiDao(os.Getenv("API_ENDPOINT")),
+		dao.NewFunctionDao(),
+		os.Getenv("DO_START_GAME_FUNCTION_NAME"))
+}
+
+func handler(request Request) (*Response, error) {
+	switch request.Action {
+	case "create":
+		tournamentId := time.Now().UTC().Format("20060102T150405Z")
+		tournament, err := model.NewTournament(tournamentId, request.Name, request.HeatSize, request.AdvancePerHeat)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println("Creating tournament", tournamentId)
+		_, err = tournamentDao.PutTournament(tournament)
+		if err != nil {
+			return nil, fmt.Errorf("error saving tournament: %w", err)
+		}
+		return &Response{Tournament: tournament}, nil
+
+	case "start":
+		fmt.Println("Starting tournament", request.TournamentId)
+		tournament, err := tournamentService.UpdateTournament(request.TournamentId, func(tournament *model.Tournament) error {
+			err := tournamentService.WithdrawDisconnected(tournament)
+			if err != nil {
+				return err
+			}
+			return tournament.Start()
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error starting tournament: %w", err)
+		}
+		err = tournamentService.StartHeats(tournament)
+		if err != nil {
+			return nil, err
+		}
+		tournamentService.SendBracketToParticipants(tournament)
+		return &Response{Tournament: tournament}, nil
+
+	case "get":
+		tournament, err := tournamentDao.GetTournament(request.TournamentId)
+		if err != nil {
+			return nil, fmt.Errorf("error getting tournament: %w", err)
+		}
+		if tournament == nil {
+			return nil, service.ErrTournamentNotFound
+		}
+		return &Response{Tournament: tournament}, nil
+	}
+
+	return nil, fmt.Errorf("unknown action: %s", request.Action)
+}
+
+func main() {
+	lambda.Start(handler)
+}
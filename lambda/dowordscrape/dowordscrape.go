@@ -8,27 +8,79 @@ import (
 	"github.com/ksanta/word-stallion/scraper"
 	"os"
 	"strconv"
+	"strings"
 )
 
 var (
 	limit    int
 	wordsDao *dao.WordsDao
+	// Why the configured storage format can't be used, if it can't
+	storageFormatErr error
+	// Local word files to include, keyed by the language of their words
+	wordFiles map[string]string
+	// Local thesaurus file to add synonyms and antonyms from
+	thesaurusFile string
 )
 
 func init() {
 	limit, _ = strconv.Atoi(os.Getenv("LIMIT"))
 	wordsDao = dao.NewWordsDao(os.Getenv("WORDS_BUCKET"))
+
+	// Defaults to uncompressed CSV if not configured. A bad format is reported by the handler.
+	if format := os.Getenv("WORDS_FORMAT"); format != "" {
+		compress, _ := strconv.ParseBool(os.Getenv("WORDS_GZIP"))
+		storageFormatErr = wordsDao.SetStorageFormat(model.WordListFormat(format), compress)
+	}
+
+	thesaurusFile = os.Getenv("THESAURUS_FILE")
+
+	// Word files are configured like "es=/opt/words_es.jsonl,fr=/opt/words_fr.jsonl"
+	wordFiles = make(map[string]string)
+	for _, wordFile := range strings.Split(os.Getenv("WORD_FILES"), ",") {
+		languageAndPath := strings.SplitN(wordFile, "=", 2)
+		if len(languageAndPath) == 2 {
+			wordFiles[languageAndPath[0]] = languageAndPath[1]
+		}
+	}
 }
 
 func handler() error {
-	fmt.Println("Scraping", limit, "words")
-	// Get a channel which pumps out word definitions
-	myScraper := scraper.NewMeriamScraper(limit)
-	wordsChan := myScraper.Scrape()
+	if storageFormatErr != nil {
+		return storageFormatErr
+	}
 
 	// Initialise words with enough capacity
 	words := make(model.Words, 0, limit)
 
+	fmt.Println("Scraping", limit, "words")
+	words = appendScrapedWords(words, scraper.NewMeriamScraper(limit))
+
+	for language, path := range wordFiles {
+		fmt.Println("Reading", language, "words from", path)
+		words = appendScrapedWords(words, scraper.NewFileScraper(path, language))
+	}
+
+	if thesaurusFile != "" {
+		fmt.Println("Adding synonyms and antonyms from", thesaurusFile)
+		err := applyThesaurus(words, thesaurusFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	version, err := wordsDao.SaveWords(words)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Saved", len(words), "words as version", version)
+	return nil
+}
+
+// appendScrapedWords appends all words from the scraper
+func appendScrapedWords(words model.Words, myScraper scraper.Scraper) model.Words {
+	// Get a channel which pumps out word definitions
+	wordsChan := myScraper.Scrape()
+
 	count := 0
 	for word := range wordsChan {
 		words = append(words, word)
@@ -37,8 +89,23 @@ func handler() error {
 			fmt.Println("Scraped", count)
 		}
 	}
+	return words
+}
+
+// applyThesaurus adds synonyms and antonyms from the thesaurus file to the words
+func applyThesaurus(words model.Words, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening thesaurus: %w", err)
+	}
+	defer file.Close()
 
-	return wordsDao.SaveWords(words)
+	thesaurus, wordErrors := model.ReadThesaurus(file)
+	for _, wordError := range wordErrors {
+		fmt.Println("Skipping thesaurus entry in", path, wordError)
+	}
+	words.ApplyThesaurus(thesaurus)
+	return nil
 }
 
 func main() {
@@ -69,7 +69,7 @@ func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayPro
 		}
 	}
 
-	players, err := playerService.SendRoundSummaryToActivePlayers(game.GameId)
+	players, err := playerService.SendRoundSummaryToActivePlayers(*game)
 	if err != nil {
 		return newErrorResponse("error ending round update to all players", err)
 	}
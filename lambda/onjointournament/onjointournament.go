@@ -0,0 +1,101 @@
+// Handles a player registering for a tournament, or asking to watch it
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ksanta/word-stallion/dao"
+	"github.com/ksanta/word-stallion/model"
+	"github.com/ksanta/word-stallion/service"
+	"os"
+)
+
+var (
+	playerService     *service.PlayerService
+	tournamentService *service.TournamentService
+)
+
+func init() {
+	apiDao := dao.NewApiDao(os.Getenv("API_ENDPOINT"))
+	// Joining only changes the tournament and messages its participants. Heats are started
+	// elsewhere, so no games, players or functions are needed.
+	playerService = service.NewPlayerService(nil, apiDao)
+	tournamentService = service.NewTournamentService(
+		dao.NewTournamentDao(os.Getenv("TOURNAMENTS_TABLE")),
+		nil,
+		nil,
+		apiDao,
+		nil,
+		"")
+}
+
+func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	connectionId := event.RequestContext.ConnectionID
+
+	// Extract the tournament from the request
+	fmt.Println("Received join tournament msg:", event.Body)
+	playerMessage := model.MessageFromPlayer{}
+	err := json.Unmarshal([]byte(event.Body), &playerMessage)
+	if err != nil {
+		return newErrorResponse("error unmarshalling JSON body", err)
+	}
+	joinTournament := playerMessage.JoinTournament
+	if joinTournament == nil {
+		return rejectJoin(connectionId, "The tournament is missing")
+	}
+
+	// Registration can be refused, which the player is told about
+	var refused error
+	tournament, err := tournamentService.UpdateTournament(joinTournament.TournamentId, func(tournament *model.Tournament) error {
+		if joinTournament.Spectate {
+			tournament.Spectate(connectionId)
+			return nil
+		}
+		refused = tournament.Register(model.Entrant{
+			ConnectionId: connectionId,
+			Name:         joinTournament.Name,
+			Icon:         joinTournament.Icon,
+		})
+		return refused
+	})
+	if errors.Is(err, service.ErrTournamentNotFound) {
+		return rejectJoin(connectionId, "There is no such tournament")
+	}
+	if refused != nil {
+		return rejectJoin(connectionId, "Can't join the tournament: "+refused.Error())
+	}
+	if err != nil {
+		return newErrorResponse("error joining tournament", err)
+	}
+
+	tournamentService.SendBracketToParticipants(tournament)
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
+// rejectJoin lets the connection know why they couldn't join the tournament
+func rejectJoin(connectionId string, message string) (events.APIGatewayProxyResponse, error) {
+	fmt.Println("Rejecting join:", message)
+	err := playerService.SendErrorToPlayer(model.Player{ConnectionId: connectionId}, message)
+	if err != nil {
+		return newErrorResponse("error sending error message", err)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
+func newErrorResponse(msg string, err error) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{
+		StatusCode: 500,
+	}, fmt.Errorf("%s: %w", msg, err)
+}
+
+func main() {
+	lambda.Start(handler)
+}
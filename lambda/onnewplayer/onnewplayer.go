@@ -16,6 +16,8 @@ import (
 var (
 	gameDao                 *dao.GameDao
 	playerDao               *dao.PlayerDao
+	dailyDao                *dao.DailyDao
+	wordsDao                *dao.WordsDao
 	apiDao                  *dao.ApiDao
 	playerService           *service.PlayerService
 	functionDao             *dao.FunctionDao
@@ -26,6 +28,8 @@ var (
 func init() {
 	gameDao = dao.NewGameDao(os.Getenv("GAMES_TABLE"))
 	playerDao = dao.NewPlayerDao(os.Getenv("PLAYERS_TABLE"))
+	dailyDao = dao.NewDailyDao(os.Getenv("DAILY_CHALLENGES_TABLE"))
+	wordsDao = dao.NewWordsDao(os.Getenv("WORDS_BUCKET"))
 	apiDao = dao.NewApiDao(os.Getenv("API_ENDPOINT"))
 	playerService = service.NewPlayerService(playerDao, apiDao)
 	functionDao = dao.NewFunctionDao()
@@ -35,40 +39,58 @@ func init() {
 }
 
 func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Get a pending game. One will be created if there isn't one yet.
-	// todo: move logic into here
-	game, err := gameDao.GetPendingGame()
-	if err != nil {
-		return newErrorResponse("Failed to get Game item", err)
-	}
-
 	// Extract the player's message from the event
 	fmt.Println("Received new player msg:", event.Body)
 	var playerMessage model.MessageFromPlayer
-	err = json.Unmarshal([]byte(event.Body), &playerMessage)
+	err := json.Unmarshal([]byte(event.Body), &playerMessage)
 	if err != nil {
 		return newErrorResponse("Error unmarshalling JSON body", err)
 	}
 	newPlayerMessage := playerMessage.NewPlayer
 
+	if newPlayerMessage.DailyChallenge {
+		return playDailyChallenge(event.RequestContext.ConnectionID, newPlayerMessage)
+	}
+	if newPlayerMessage.Practice || newPlayerMessage.Review {
+		return startPractice(event.RequestContext.ConnectionID, newPlayerMessage)
+	}
+
+	// Get a pending game. One will be created if there isn't one yet.
+	// todo: move logic into here
+	game, err := gameDao.GetPendingGame(event.RequestContext.ConnectionID)
+	if err != nil {
+		return newErrorResponse("Failed to get Game item", err)
+	}
+
+	// Put the player in a team for team races
+	team := ""
+	if game.IsTeamRace() {
+		fmt.Println("Getting players")
+		players, err := playerDao.GetPlayers(game.GameId)
+		if err != nil {
+			return newErrorResponse("Error getting players", err)
+		}
+		team = game.AssignTeam(players, newPlayerMessage.Team)
+	}
+
 	// Create a new Player item in Dynamo
 	fmt.Println("Saving new player:", event.RequestContext.ConnectionID)
 	millisSinceGameCreated := time.Since(game.CreatedAt).Milliseconds()
 	player, err := playerDao.AddNewPlayer(event.RequestContext.ConnectionID,
-		game.GameId, millisSinceGameCreated, newPlayerMessage.Name, newPlayerMessage.Icon)
+		game.GameId, millisSinceGameCreated, newPlayerMessage.Name, newPlayerMessage.Icon, team, newPlayerMessage.PlayerId)
 	if err != nil {
 		return newErrorResponse("Error saving new player", err)
 	}
 
 	// Send a welcome message to the player
 	secondsTillStart := game.GameStartTime.Sub(time.Now()).Seconds()
-	err = playerService.SendWelcomeMessageToPlayer(*player, game.TargetScore, int(secondsTillStart))
+	err = playerService.SendWelcomeMessageToPlayer(*player, *game, int(secondsTillStart))
 	if err != nil {
 		return newErrorResponse("Error posting welcome message to the player", err)
 	}
 
 	// Send a "round summary" message to all active players
-	players, err := playerService.SendRoundSummaryToActivePlayers(game.GameId)
+	players, err := playerService.SendRoundSummaryToActivePlayers(*game)
 	if err != nil {
 		return newErrorResponse("Error sending a message to all players", err)
 	}
@@ -96,6 +118,118 @@ func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayPro
 	}, nil
 }
 
+// playDailyChallenge starts a solo game of today's daily challenge. Players who have already
+// played it are shown the leaderboard instead.
+func playDailyChallenge(connectionId string, newPlayerMessage *model.NewPlayer) (events.APIGatewayProxyResponse, error) {
+	// Messages are addressed by connection id, so a player is all that's needed until they join
+	connection := model.Player{ConnectionId: connectionId}
+	if newPlayerMessage.PlayerId == "" {
+		return rejectNewPlayer(connection, "A player id is needed to play the daily challenge")
+	}
+
+	// The first play of the day pins the challenge to the current version of the corpus
+	version, err := wordsDao.GetCurrentVersion()
+	if err != nil {
+		return newErrorResponse("Error getting words version", err)
+	}
+	date := model.DailyDate(time.Now())
+	fmt.Println("Claiming daily challenge", date, "for player", newPlayerMessage.PlayerId)
+	challenge, claimed, err := dailyDao.ClaimDailyChallenge(date, newPlayerMessage.PlayerId, version)
+	if err != nil {
+		return newErrorResponse("Error claiming daily challenge", err)
+	}
+	if !claimed {
+		challenge, err = dailyDao.GetDailyChallenge(date)
+		if err != nil {
+			return newErrorResponse("Error getting daily challenge", err)
+		}
+		err = playerService.SendDailyLeaderboardToPlayer(connection, challenge)
+		if err != nil {
+			return newErrorResponse("Error posting daily leaderboard to the player", err)
+		}
+		return rejectNewPlayer(connection, "You have already played today's daily challenge")
+	}
+
+	gameId := fmt.Sprintf("daily-%s-%s", date, connectionId)
+	fmt.Println("Creating daily challenge game:", gameId)
+	game, err := gameDao.CreateDailyGame(gameId, challenge)
+	if err != nil {
+		return newErrorResponse("Error creating daily challenge game", err)
+	}
+
+	fmt.Println("Saving new player:", connectionId)
+	player, err := playerDao.AddNewPlayer(connectionId, game.GameId, 0,
+		newPlayerMessage.Name, newPlayerMessage.Icon, "", newPlayerMessage.PlayerId)
+	if err != nil {
+		return newErrorResponse("Error saving new player", err)
+	}
+
+	// There is nobody to wait for, so start straight away
+	err = playerService.SendWelcomeMessageToPlayer(*player, *game, 0)
+	if err != nil {
+		return newErrorResponse("Error posting welcome message to the player", err)
+	}
+	err = functionDao.InvokeStartGame(doStartGameFunctionName, game.GameId)
+	if err != nil {
+		return newErrorResponse("Error invoking start game function", err)
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
+// startPractice starts a game for the player on their own, without waiting for others to join.
+// Review games ask the words the player has missed before.
+func startPractice(connectionId string, newPlayerMessage *model.NewPlayer) (events.APIGatewayProxyResponse, error) {
+	reviewFor := ""
+	if newPlayerMessage.Review {
+		if newPlayerMessage.PlayerId == "" {
+			return rejectNewPlayer(model.Player{ConnectionId: connectionId}, "A player id is needed to review missed words")
+		}
+		reviewFor = newPlayerMessage.PlayerId
+	}
+
+	gameId := fmt.Sprintf("practice-%s-%s", time.Now().Format(time.RFC3339), connectionId)
+	fmt.Println("Creating practice game:", gameId)
+	game, err := gameDao.CreatePracticeGame(gameId, reviewFor)
+	if err != nil {
+		return newErrorResponse("Error creating practice game", err)
+	}
+
+	fmt.Println("Saving new player:", connectionId)
+	player, err := playerDao.AddNewPlayer(connectionId, game.GameId, 0,
+		newPlayerMessage.Name, newPlayerMessage.Icon, "", newPlayerMessage.PlayerId)
+	if err != nil {
+		return newErrorResponse("Error saving new player", err)
+	}
+
+	err = playerService.SendWelcomeMessageToPlayer(*player, *game, 0)
+	if err != nil {
+		return newErrorResponse("Error posting welcome message to the player", err)
+	}
+	err = functionDao.InvokeStartGame(doStartGameFunctionName, game.GameId)
+	if err != nil {
+		return newErrorResponse("Error invoking start game function", err)
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
+// rejectNewPlayer lets the player know why they can't play
+func rejectNewPlayer(player model.Player, message string) (events.APIGatewayProxyResponse, error) {
+	fmt.Println("Rejecting new player:", message)
+	err := playerService.SendErrorToPlayer(player, message)
+	if err != nil {
+		return newErrorResponse("Error sending error message", err)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
 func newErrorResponse(msg string, err error) (events.APIGatewayProxyResponse, error) {
 	return events.APIGatewayProxyResponse{
 		StatusCode: 500,
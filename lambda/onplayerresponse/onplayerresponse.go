@@ -8,26 +8,39 @@ import (
 	"github.com/ksanta/word-stallion/dao"
 	"github.com/ksanta/word-stallion/model"
 	"github.com/ksanta/word-stallion/service"
+	"math/rand"
 	"os"
 	"time"
 )
 
 var (
-	gameDao             *dao.GameDao
-	playerDao           *dao.PlayerDao
-	playerService       *service.PlayerService
-	functionDao         *dao.FunctionDao
-	doRoundFunctionName string
+	gameDao                         *dao.GameDao
+	playerDao                       *dao.PlayerDao
+	dailyDao                        *dao.DailyDao
+	reviewDao                       *dao.ReviewDao
+	flaggedGameDao                  *dao.FlaggedGameDao
+	playerService                   *service.PlayerService
+	functionDao                     *dao.FunctionDao
+	doRoundFunctionName             string
+	doAdvanceTournamentFunctionName string
 )
 
+// maxPowerUpAttempts limits how many times saving an earned power-up is retried when the
+// player's power-ups are changed at the same time
+const maxPowerUpAttempts = 3
+
 func init() {
 	gameDao = dao.NewGameDao(os.Getenv("GAMES_TABLE"))
 	playerDao = dao.NewPlayerDao(os.Getenv("PLAYERS_TABLE"))
+	dailyDao = dao.NewDailyDao(os.Getenv("DAILY_CHALLENGES_TABLE"))
+	reviewDao = dao.NewReviewDao(os.Getenv("REVIEW_DECKS_TABLE"))
+	flaggedGameDao = dao.NewFlaggedGameDao(os.Getenv("FLAGGED_GAMES_TABLE"))
 	apiDao := dao.NewApiDao(os.Getenv("API_ENDPOINT"))
 	playerService = service.NewPlayerService(playerDao, apiDao)
 
 	functionDao = dao.NewFunctionDao()
 	doRoundFunctionName = os.Getenv("DO_ROUND_FUNCTION_NAME")
+	doAdvanceTournamentFunctionName = os.Getenv("DO_ADVANCE_TOURNAMENT_FUNCTION_NAME")
 }
 
 func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
@@ -44,6 +57,13 @@ func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayPro
 			StatusCode: 200,
 		}, nil
 	}
+	// Eliminated players and those not in a sudden-death round watch without answering
+	if !player.IsRacing() {
+		fmt.Println("Player isn't racing - ignoring")
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+		}, nil
+	}
 	player.Responded = true
 
 	fmt.Println("Getting game")
@@ -52,6 +72,14 @@ func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayPro
 		return newErrorResponse("error fetching game", err)
 	}
 
+	// Sudden death can finish the game before everyone has responded
+	if game.GameState != model.InProgress {
+		fmt.Println("Game is not in progress - ignoring")
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+		}, nil
+	}
+
 	// Extract player response from the request
 	playerMessage := model.MessageFromPlayer{}
 	err = json.Unmarshal([]byte(event.Body), &playerMessage)
@@ -60,26 +88,99 @@ func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayPro
 	}
 
 	// Award points to the player
-	playerResponse := playerMessage.PlayerResponse.Response
-	fmt.Printf("%s responded with %d\n", player.Name, playerResponse)
-	pointsForRound := game.CalculatePoints(playerResponse, time.Now())
+	playerResponse := *playerMessage.PlayerResponse
+	if game.RoundMode.IsMultipleChoice() {
+		fmt.Printf("%s responded with %d\n", player.Name, playerResponse.Response)
+	} else {
+		fmt.Printf("%s responded with %q\n", player.Name, playerResponse.Text)
+	}
+	// Options may have been removed from what the player was shown
+	playerResponse.Response = player.OriginalOption(playerResponse.Response)
+	scoredResponse := game.ScoreResponse(*player, playerResponse, time.Now())
+	// Keep the evidence against responses too fast or too regular to come from a person
+	if reason, evidence, suspicious := model.DetectCheating(*player, scoredResponse); suspicious {
+		fmt.Printf("Flagging %s for %s: %s\n", player.Name, reason, evidence)
+		scoredResponse.NoSpeedPoints = game.ZeroFlaggedSpeedPoints
+		flag := game.NewCheatFlag(*player, scoredResponse, reason, evidence, time.Now())
+		err = flaggedGameDao.AddCheatFlag(game.GameId, flag)
+		if err != nil {
+			return newErrorResponse("error flagging game", err)
+		}
+	}
+	if (game.ScoringPolicy == model.FirstCorrectScoring || game.SuddenDeath) && scoredResponse.IsCorrect() {
+		scoredResponse.FirstCorrect, err = gameDao.ClaimFirstCorrect(game.GameId, game.RoundNumber, player.ConnectionId)
+		if err != nil {
+			return newErrorResponse("error claiming first correct answer", err)
+		}
+	}
+	// Points don't count in sudden death - the first correct answer wins
+	pointsForRound := 0
+	if game.SuddenDeath {
+		player.WonSuddenDeath = scoredResponse.FirstCorrect
+	} else {
+		pointsForRound = game.CalculatePoints(scoredResponse)
+	}
 	fmt.Printf("%s awarded %d points\n", player.Name, pointsForRound)
 	player.Points += pointsForRound
+	player.RoundPoints = pointsForRound
+	player.ResponseMillis = scoredResponse.Elapsed.Milliseconds()
+	player.TotalResponseMillis += player.ResponseMillis
+	player.RoundsAnswered++
+	player.RoundAccuracy = scoredResponse.Accuracy
+	held := len(player.PowerUps)
+	if scoredResponse.IsCorrect() {
+		player.Streak++
+		player.CorrectAnswers++
+		player.CorrectResponseMillis = append(player.CorrectResponseMillis, player.ResponseMillis)
+		// The power-up earned is the player's own, so it isn't picked from the game's seed
+		player.PowerUpEarned = player.EarnPowerUp(rand.New(rand.NewSource(time.Now().UnixNano())))
+	} else {
+		player.Streak = 0
+	}
 
-	// Save player's updated attributes
+	// Save player's updated attributes. Only what scoring changes is saved, so a power-up used at
+	// the same time isn't lost.
+	if player.PowerUpEarned != "" {
+		fmt.Println("Saving power-ups")
+		err = savePowerUpEarned(player, held)
+		if err != nil {
+			return newErrorResponse("error saving power-ups", err)
+		}
+	}
 	fmt.Println("Saving player")
-	err = playerDao.PutPlayer(player)
+	err = playerDao.UpdatePlayer(*player, scoredAttributes...)
 	if err != nil {
 		return newErrorResponse("error saving player", err)
 	}
 
-	// Asynchronously send the correct answer to the player
-	go func() {
-		err = playerService.SendCorrectAnswerToPlayer(*player, playerResponse == game.CorrectAnswer, game.CorrectAnswer)
+	// Words the player gets wrong are scheduled for review
+	if player.PlayerId != "" && game.RoundWord != "" {
+		err = recordReview(*player, *game, model.ReviewQuality(scoredResponse))
+		if err != nil {
+			return newErrorResponse("error recording review", err)
+		}
+	}
+
+	// Practice games are reviewed at the end
+	if game.IsPractice() {
+		game.RecordPracticeAnswer(scoredResponse.IsCorrect())
+		err = gameDao.PutGame(game)
 		if err != nil {
-			fmt.Printf("error sending correct answer to player: %s\n", err)
+			return newErrorResponse("error saving game", err)
 		}
-	}()
+	}
+
+	// Asynchronously send the correct answer to the player, unless results are kept until the
+	// round closes
+	if !game.WithholdResults {
+		playerResult := game.PlayerResult(*player)
+		go func() {
+			err = playerService.SendCorrectAnswerToPlayer(*player, playerResult)
+			if err != nil {
+				fmt.Printf("error sending correct answer to player: %s\n", err)
+			}
+		}()
+	}
 
 	fmt.Println("Getting players")
 	players, err := playerDao.GetPlayers(game.GameId)
@@ -87,21 +188,57 @@ func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayPro
 		return newErrorResponse("error fetching players", err)
 	}
 
-	// Asynchronously send this player's update to all active players
-	go func() {
-		err := playerService.SendPlayerUpdateToActivePlayers(players, player.PlayerState())
-		if err != nil {
-			fmt.Printf("error sending player update: %s\n", err)
+	// Asynchronously send this player's update to all active players. Nobody else is playing a
+	// practice game, and a new score would give away a withheld result.
+	if !game.IsPractice() && !game.WithholdResults {
+		go func() {
+			err := playerService.SendPlayerUpdateToActivePlayers(*game, players, player.PlayerState())
+			if err != nil {
+				fmt.Printf("error sending player update: %s\n", err)
+			}
+		}()
+	}
+
+	// If all players have responded, or sudden death has been won, do another round or finish the game
+	if players.AllActivePlayersResponded() || player.WonSuddenDeath {
+		// Everyone finds out how they did now the round is closed
+		if game.WithholdResults {
+			playerService.SendRoundResultsToActivePlayers(*game, players)
+		}
+
+		// Knock out the player who did worst in elimination races
+		if eliminated := game.Eliminate(players); eliminated != nil {
+			fmt.Println(eliminated.Name, "eliminated in round", eliminated.EliminatedInRound)
+			err = playerDao.UpdatePlayer(*eliminated, "eliminated", "eliminated_in_round")
+			if err != nil {
+				return newErrorResponse("error saving eliminated player", err)
+			}
+			playerService.SendEliminatedToActivePlayers(players, *eliminated)
+		}
+
+		// Players tied for first may have to race on in sudden death
+		suddenDeath := game.IsOver(players, time.Now()) && game.NeedsSuddenDeath(players)
+		if suddenDeath {
+			inSuddenDeath := game.StartSuddenDeath(players)
+			fmt.Println("Starting sudden death between", len(inSuddenDeath), "players")
+			err = gameDao.PutGame(game)
+			if err != nil {
+				return newErrorResponse("error saving game", err)
+			}
+			err = playerDao.UpdatePlayers(players, "spectating")
+			if err != nil {
+				return newErrorResponse("error saving players", err)
+			}
+			playerService.SendSuddenDeathToActivePlayers(players, inSuddenDeath)
 		}
-	}()
 
-	// If all players have responded, send round update, and do another round or finish the game
-	if players.AllActivePlayersResponded() {
-		if players.PlayerWithHighestPoints().Points < game.TargetScore {
+		if suddenDeath || !game.IsOver(players, time.Now()) {
 			// todo: let players know that the round is finished?
-			// Do another round if the target score is not yet reached
-			fmt.Println("Sleeping two seconds")
-			time.Sleep(2 * time.Second)
+			// Do another round if the game hasn't been won yet. Practice games move straight on.
+			if !game.IsPractice() {
+				fmt.Println("Sleeping two seconds")
+				time.Sleep(2 * time.Second)
+			}
 			fmt.Print("Invoking DoRound")
 			err = functionDao.InvokeDoRound(doRoundFunctionName, game.GameId)
 			if err != nil {
@@ -109,7 +246,7 @@ func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayPro
 			}
 		} else {
 			// Game is finished - send winner to all players
-			err = playerService.SendGameSummaryToAllActivePlayers(players)
+			err = playerService.SendGameSummaryToAllActivePlayers(*game, players)
 			if err != nil {
 				return newErrorResponse("error sending game summary to players", err)
 			}
@@ -121,6 +258,23 @@ func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayPro
 			if err != nil {
 				return newErrorResponse("error saving game", err)
 			}
+
+			// Tournament heats advance their top players
+			if game.TournamentId != "" {
+				fmt.Println("Invoking AdvanceTournament")
+				err = functionDao.InvokeAdvanceTournament(doAdvanceTournamentFunctionName, game.GameId)
+				if err != nil {
+					return newErrorResponse("error invoking AdvanceTournament", err)
+				}
+			}
+
+			// Daily challenge players go on the day's leaderboard
+			if game.IsDailyChallenge() {
+				err = recordDailyResults(game, players)
+				if err != nil {
+					return newErrorResponse("error recording daily challenge results", err)
+				}
+			}
 		}
 	}
 
@@ -129,6 +283,89 @@ func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayPro
 	}, nil
 }
 
+// scoredAttributes are the attributes of a player changed by scoring their response
+var scoredAttributes = []string{
+	"responded", "won_sudden_death", "points", "round_points", "response_millis",
+	"total_response_millis", "rounds_answered", "round_accuracy", "streak", "correct_answers",
+	"correct_response_millis", "power_up_earned",
+}
+
+// savePowerUpEarned saves the power-up the player earned, given how many they held before. If
+// they used one at the same time, the earned power-up is added to what they hold now.
+func savePowerUpEarned(player *model.Player, held int) error {
+	earned := player.PowerUpEarned
+	for attempt := 0; ; attempt++ {
+		saved, err := playerDao.SavePowerUpEarned(*player, held)
+		if err != nil {
+			return err
+		}
+		if saved {
+			return nil
+		}
+		if attempt+1 >= maxPowerUpAttempts {
+			return fmt.Errorf("gave up after %d attempts", maxPowerUpAttempts)
+		}
+
+		fmt.Println("Power-ups were changed by someone else - retrying")
+		current, err := playerDao.GetPlayer(player.ConnectionId)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return nil
+		}
+		held = len(current.PowerUps)
+		player.QueuedPowerUps = current.QueuedPowerUps
+		player.PowerUps = current.PowerUps
+		if held >= model.MaxPowerUps {
+			player.PowerUpEarned = ""
+			return nil
+		}
+		player.PowerUps = append(player.PowerUps[:held:held], earned)
+	}
+}
+
+// recordReview schedules the next review of the round's word for the player
+func recordReview(player model.Player, game model.Game, quality int) error {
+	deck, err := reviewDao.GetReviewDeck(player.PlayerId)
+	if err != nil {
+		return err
+	}
+	if !deck.RecordAnswer(game.RoundWord, game.Language, quality, time.Now()) {
+		return nil
+	}
+	fmt.Println("Scheduling review of", game.RoundWord, "for", player.Name)
+	return reviewDao.PutReviewDeck(deck)
+}
+
+// recordDailyResults adds the players' results to the daily leaderboard and shows it to them
+func recordDailyResults(game *model.Game, players model.Players) error {
+	for _, player := range players {
+		if player.PlayerId == "" {
+			continue
+		}
+		fmt.Println("Adding", player.Name, "to the daily leaderboard for", game.DailyDate)
+		err := dailyDao.AddDailyResult(game.DailyDate, model.NewDailyResult(*player, time.Now()))
+		if err != nil {
+			return err
+		}
+	}
+
+	challenge, err := dailyDao.GetDailyChallenge(game.DailyDate)
+	if err != nil {
+		return err
+	}
+	for _, player := range players {
+		if player.Active {
+			err = playerService.SendDailyLeaderboardToPlayer(*player, challenge)
+			if err != nil {
+				fmt.Println("Error posting daily leaderboard to player", err)
+			}
+		}
+	}
+	return nil
+}
+
 func newErrorResponse(msg string, err error) (events.APIGatewayProxyResponse, error) {
 	return events.APIGatewayProxyResponse{
 		StatusCode: 500,
@@ -0,0 +1,131 @@
+// Handles the game creator changing the rules of the game before it starts
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ksanta/word-stallion/dao"
+	"github.com/ksanta/word-stallion/model"
+	"github.com/ksanta/word-stallion/service"
+	"os"
+	"time"
+)
+
+var (
+	gameDao       *dao.GameDao
+	playerDao     *dao.PlayerDao
+	playerService *service.PlayerService
+)
+
+func init() {
+	gameDao = dao.NewGameDao(os.Getenv("GAMES_TABLE"))
+	playerDao = dao.NewPlayerDao(os.Getenv("PLAYERS_TABLE"))
+	apiDao := dao.NewApiDao(os.Getenv("API_ENDPOINT"))
+	playerService = service.NewPlayerService(playerDao, apiDao)
+}
+
+func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	fmt.Println("Getting player")
+	player, err := playerDao.GetPlayer(event.RequestContext.ConnectionID)
+	if err != nil {
+		return newErrorResponse("error fetching player", err)
+	}
+
+	// Ignore rules from connections that haven't joined a game
+	if player == nil {
+		fmt.Println("Rules from unregistered player - ignoring")
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+		}, nil
+	}
+
+	fmt.Println("Getting game")
+	game, err := gameDao.GetGame(player.GameId)
+	if err != nil {
+		return newErrorResponse("error fetching game", err)
+	}
+
+	if game.GameState != model.Pending {
+		return rejectRules(*player, "The rules can only be changed before the game starts")
+	}
+	// Everyone plays the same daily challenge
+	if game.IsDailyChallenge() {
+		return rejectRules(*player, "The rules of the daily challenge can't be changed")
+	}
+
+	if !game.IsCreator(*player) {
+		return rejectRules(*player, "Only the player who created the game can change the rules")
+	}
+
+	// Extract the rules from the request
+	fmt.Println("Received rules:", event.Body)
+	playerMessage := model.MessageFromPlayer{}
+	err = json.Unmarshal([]byte(event.Body), &playerMessage)
+	if err != nil {
+		return newErrorResponse("error unmarshalling JSON body", err)
+	}
+	if playerMessage.SetRules == nil {
+		return rejectRules(*player, "The rules are missing")
+	}
+
+	err = game.ApplyRules(*playerMessage.SetRules)
+	if err != nil {
+		return rejectRules(*player, "The rules are invalid: "+err.Error())
+	}
+
+	// The game may have started since it was fetched
+	stillPending, err := gameDao.PutPendingGame(game)
+	if err != nil {
+		return newErrorResponse("error saving game", err)
+	}
+	if !stillPending {
+		return rejectRules(*player, "The rules can only be changed before the game starts")
+	}
+
+	fmt.Println("Getting players")
+	players, err := playerDao.GetPlayers(game.GameId)
+	if err != nil {
+		return newErrorResponse("error fetching players", err)
+	}
+
+	// Players may need to move team if the teams have changed
+	if game.AssignTeams(players) {
+		fmt.Println("Saving players in their new teams")
+		err = playerDao.UpdatePlayers(players, "team")
+		if err != nil {
+			return newErrorResponse("error saving players", err)
+		}
+	}
+
+	// Let everyone waiting know about the new rules
+	secondsTillStart := game.GameStartTime.Sub(time.Now()).Seconds()
+	playerService.SendWelcomeMessageToActivePlayers(players, *game, int(secondsTillStart))
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
+// rejectRules lets the player know why their rules were not accepted
+func rejectRules(player model.Player, message string) (events.APIGatewayProxyResponse, error) {
+	fmt.Println("Rejecting rules:", message)
+	err := playerService.SendErrorToPlayer(player, message)
+	if err != nil {
+		return newErrorResponse("error sending error message", err)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
+func newErrorResponse(msg string, err error) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{
+		StatusCode: 500,
+	}, fmt.Errorf("%s: %w", msg, err)
+}
+
+func main() {
+	lambda.Start(handler)
+}
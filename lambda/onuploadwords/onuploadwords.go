@@ -0,0 +1,130 @@
+// Handles the game creator uploading their own word list for the game
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ksanta/word-stallion/dao"
+	"github.com/ksanta/word-stallion/model"
+	"github.com/ksanta/word-stallion/service"
+	"os"
+)
+
+var (
+	gameDao       *dao.GameDao
+	playerDao     *dao.PlayerDao
+	wordsDao      *dao.WordsDao
+	playerService *service.PlayerService
+)
+
+func init() {
+	gameDao = dao.NewGameDao(os.Getenv("GAMES_TABLE"))
+	playerDao = dao.NewPlayerDao(os.Getenv("PLAYERS_TABLE"))
+	wordsDao = dao.NewWordsDao(os.Getenv("WORDS_BUCKET"))
+	// Uploaded words can have examples and related words, which only JSON Lines keeps
+	_ = wordsDao.SetStorageFormat(model.JSONLinesFormat, false)
+	apiDao := dao.NewApiDao(os.Getenv("API_ENDPOINT"))
+	playerService = service.NewPlayerService(playerDao, apiDao)
+}
+
+func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	fmt.Println("Getting player")
+	player, err := playerDao.GetPlayer(event.RequestContext.ConnectionID)
+	if err != nil {
+		return newErrorResponse("error fetching player", err)
+	}
+
+	// Ignore uploads from connections that haven't joined a game
+	if player == nil {
+		fmt.Println("Word list from unregistered player - ignoring")
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+		}, nil
+	}
+
+	fmt.Println("Getting game")
+	game, err := gameDao.GetGame(player.GameId)
+	if err != nil {
+		return newErrorResponse("error fetching game", err)
+	}
+
+	if game.GameState != model.Pending {
+		return rejectUpload(*player, "The word list can only be changed before the game starts")
+	}
+	// Everyone plays the same daily challenge
+	if game.IsDailyChallenge() {
+		return rejectUpload(*player, "The daily challenge can't use your own word list")
+	}
+
+	if !game.IsCreator(*player) {
+		return rejectUpload(*player, "Only the player who created the game can upload a word list")
+	}
+
+	// Extract the word list from the request
+	playerMessage := model.MessageFromPlayer{}
+	err = json.Unmarshal([]byte(event.Body), &playerMessage)
+	if err != nil {
+		return newErrorResponse("error unmarshalling JSON body", err)
+	}
+	if playerMessage.UploadWords == nil {
+		return rejectUpload(*player, "The word list is missing")
+	}
+
+	// Reject the whole list so the creator can fix every bad row before playing
+	words, wordErrors := model.ReadWordList(playerMessage.UploadWords.Format, playerMessage.UploadWords.Content)
+	if len(wordErrors) > 0 {
+		return rejectUpload(*player, "The word list is invalid: "+wordErrors.Error())
+	}
+	if len(model.PlayableTypes(words.GroupByType(), game.OptionsPerQuestion)) == 0 {
+		message := fmt.Sprintf("The word list needs at least %d words of the same type", game.OptionsPerQuestion)
+		return rejectUpload(*player, message)
+	}
+
+	fmt.Println("Saving", len(words), "words for game", game.GameId)
+	err = wordsDao.SaveGameWords(game.GameId, words)
+	if err != nil {
+		return newErrorResponse("error saving words", err)
+	}
+
+	// The game may have started while the words were being saved
+	stillPending, err := gameDao.UseCustomWords(game.GameId)
+	if err != nil {
+		return newErrorResponse("error saving game", err)
+	}
+	if !stillPending {
+		return rejectUpload(*player, "The word list can only be changed before the game starts")
+	}
+
+	err = playerService.SendWordsUploadedToPlayer(*player, len(words))
+	if err != nil {
+		return newErrorResponse("error sending words uploaded message", err)
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
+// rejectUpload lets the player know why their word list was not accepted
+func rejectUpload(player model.Player, message string) (events.APIGatewayProxyResponse, error) {
+	fmt.Println("Rejecting word list:", message)
+	err := playerService.SendErrorToPlayer(player, message)
+	if err != nil {
+		return newErrorResponse("error sending error message", err)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
+func newErrorResponse(msg string, err error) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{
+		StatusCode: 500,
+	}, fmt.Errorf("%s: %w", msg, err)
+}
+
+func main() {
+	lambda.Start(handler)
+}
@@ -0,0 +1,137 @@
+// Handles a player spending a power-up on the next round
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/ksanta/word-stallion/dao"
+	"github.com/ksanta/word-stallion/model"
+	"github.com/ksanta/word-stallion/service"
+	"os"
+)
+
+var (
+	gameDao       *dao.GameDao
+	playerDao     *dao.PlayerDao
+	playerService *service.PlayerService
+)
+
+// maxPowerUpAttempts limits how many times using a power-up is retried when the player's
+// power-ups are changed at the same time
+const maxPowerUpAttempts = 3
+
+func init() {
+	gameDao = dao.NewGameDao(os.Getenv("GAMES_TABLE"))
+	playerDao = dao.NewPlayerDao(os.Getenv("PLAYERS_TABLE"))
+	apiDao := dao.NewApiDao(os.Getenv("API_ENDPOINT"))
+	playerService = service.NewPlayerService(playerDao, apiDao)
+}
+
+func handler(event events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	fmt.Println("Getting player")
+	player, err := playerDao.GetPlayer(event.RequestContext.ConnectionID)
+	if err != nil {
+		return newErrorResponse("error fetching player", err)
+	}
+
+	// Ignore power-ups from connections that haven't joined a game
+	if player == nil {
+		fmt.Println("Power-up from unregistered player - ignoring")
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+		}, nil
+	}
+
+	fmt.Println("Getting game")
+	game, err := gameDao.GetGame(player.GameId)
+	if err != nil {
+		return newErrorResponse("error fetching game", err)
+	}
+	if game.GameState != model.InProgress {
+		return rejectPowerUp(*player, "Power-ups can only be used during a game")
+	}
+	// Power-ups take effect in the player's next round, so only those still racing can use them
+	if !player.IsRacing() {
+		return rejectPowerUp(*player, "Power-ups can only be used while you're racing")
+	}
+	if player.Responded {
+		return rejectPowerUp(*player, "Power-ups can't be used once you've answered")
+	}
+
+	// Extract the power-up from the request
+	playerMessage := model.MessageFromPlayer{}
+	err = json.Unmarshal([]byte(event.Body), &playerMessage)
+	if err != nil {
+		return newErrorResponse("error unmarshalling JSON body", err)
+	}
+	if playerMessage.UsePowerUp == nil || !playerMessage.UsePowerUp.PowerUp.IsValid() {
+		return rejectPowerUp(*player, "Unknown power-up")
+	}
+
+	powerUp := playerMessage.UsePowerUp.PowerUp
+	for attempt := 0; ; attempt++ {
+		index := player.HeldPowerUp(powerUp)
+		if index < 0 {
+			return rejectPowerUp(*player, fmt.Sprintf("You don't have a %s power-up", powerUp))
+		}
+
+		// Only the power-ups are saved, so a response being scored at the same time isn't lost
+		fmt.Println("Saving power-ups")
+		saved, err := playerDao.UsePowerUp(*player, index)
+		if err != nil {
+			return newErrorResponse("error saving power-ups", err)
+		}
+		if saved {
+			player.UsePowerUp(powerUp)
+			break
+		}
+		if attempt+1 >= maxPowerUpAttempts {
+			return newErrorResponse("error saving power-ups", fmt.Errorf("gave up after %d attempts", maxPowerUpAttempts))
+		}
+
+		fmt.Println("Power-ups were changed by someone else - retrying")
+		player, err = playerDao.GetPlayer(player.ConnectionId)
+		if err != nil {
+			return newErrorResponse("error fetching player", err)
+		}
+		if player == nil {
+			return events.APIGatewayProxyResponse{
+				StatusCode: 200,
+			}, nil
+		}
+	}
+	fmt.Printf("%s used %s\n", player.Name, powerUp)
+
+	err = playerService.SendPowerUpsToPlayer(*player)
+	if err != nil {
+		return newErrorResponse("error sending power-ups", err)
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
+// rejectPowerUp lets the player know why their power-up was not used
+func rejectPowerUp(player model.Player, message string) (events.APIGatewayProxyResponse, error) {
+	fmt.Println("Rejecting power-up:", message)
+	err := playerService.SendErrorToPlayer(player, message)
+	if err != nil {
+		return newErrorResponse("error sending error message", err)
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+	}, nil
+}
+
+func newErrorResponse(msg string, err error) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{
+		StatusCode: 500,
+	}, fmt.Errorf("%s: %w", msg, err)
+}
+
+func main() {
+	lambda.Start(handler)
+}
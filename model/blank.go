@@ -0,0 +1,84 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Blank replaces the word in fill in the blank sentences
+const Blank = "_____"
+
+// wordPattern matches each word in a sentence, including contractions and hyphenated words
+var wordPattern = regexp.MustCompile(`[\pL]+(?:['’-][\pL]+)*`)
+
+// inflectionSuffixes are the endings added to a word to make its inflected forms
+var inflectionSuffixes = []string{"", "s", "es", "d", "ed", "ing", "er", "est", "ly", "ness"}
+
+// stemSuffixes are the endings added to a word after its spelling is changed, such as
+// "happy" becoming "happi" before "ly"
+var stemSuffixes = []string{"es", "ed", "ing", "er", "est", "ly", "ness"}
+
+// BlankOutWord replaces the word, or any inflected form of it, in the sentence with a blank.
+// Returns false if the word isn't in the sentence.
+func BlankOutWord(sentence string, word string) (string, bool) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word == "" {
+		return sentence, false
+	}
+
+	// Phrases are only blanked out where they appear exactly
+	if strings.Contains(word, " ") {
+		phrasePattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(word) + `\b`)
+		if !phrasePattern.MatchString(sentence) {
+			return sentence, false
+		}
+		return phrasePattern.ReplaceAllString(sentence, Blank), true
+	}
+
+	forms := inflectedForms(word)
+	blanked := false
+	blankedSentence := wordPattern.ReplaceAllStringFunc(sentence, func(token string) string {
+		if _, present := forms[strings.ToLower(token)]; present {
+			blanked = true
+			return Blank
+		}
+		return token
+	})
+	return blankedSentence, blanked
+}
+
+// inflectedForms returns the ways a word can appear in a sentence, following the regular
+// English spelling rules
+func inflectedForms(word string) map[string]interface{} {
+	forms := make(map[string]interface{})
+	for _, suffix := range inflectionSuffixes {
+		forms[word+suffix] = struct{}{}
+	}
+
+	// Some suffixes change the spelling of the end of the word
+	runes := []rune(word)
+	last := runes[len(runes)-1]
+	stem := ""
+	switch {
+	case last == 'y' && len(runes) > 1 && !isVowel(runes[len(runes)-2]):
+		// happy -> happier, happily
+		stem = string(runes[:len(runes)-1]) + "i"
+	case last == 'e':
+		// wade -> wading, waded
+		stem = string(runes[:len(runes)-1])
+	case len(runes) > 2 && !isVowel(last) && isVowel(runes[len(runes)-2]) && !isVowel(runes[len(runes)-3]):
+		// plod -> plodding, plodded
+		stem = word + string(last)
+	}
+	if stem != "" {
+		for _, suffix := range stemSuffixes {
+			forms[stem+suffix] = struct{}{}
+		}
+	}
+
+	return forms
+}
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune("aeiou", r)
+}
@@ -0,0 +1,33 @@
+package model
+
+import "testing"
+
+func TestBlankOutWord(t *testing.T) {
+	tests := []struct {
+		sentence string
+		word     string
+		expected string
+	}{
+		{"The cat sat.", "cat", "The _____ sat."},
+		{"Cats are aloof.", "cat", "_____ are aloof."},
+		{"She was wading through it.", "wade", "She was _____ through it."},
+		{"He plodded home happily.", "plod", "He _____ home happily."},
+		{"He plodded home happily.", "happy", "He plodded home _____."},
+		{"They studied all night.", "study", "They _____ all night."},
+		{"It was done in medias res.", "in medias res", "It was done _____."},
+	}
+
+	for _, test := range tests {
+		got, blanked := BlankOutWord(test.sentence, test.word)
+		if !blanked || got != test.expected {
+			t.Errorf("Blanking %q from %q: got %q and expected %q", test.word, test.sentence, got, test.expected)
+		}
+	}
+}
+
+func TestBlankOutWord_NotInSentence(t *testing.T) {
+	_, blanked := BlankOutWord("The wad of cash.", "wade")
+	if blanked {
+		t.Errorf("Expected wad not to be blanked for wade")
+	}
+}
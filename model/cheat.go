@@ -0,0 +1,109 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// HumanResponseThreshold is the fastest a person can read a question and answer it correctly
+	HumanResponseThreshold = 300 * time.Millisecond
+	// consistencyRounds is how many correct answers in a row are compared for implausibly
+	// consistent response times
+	consistencyRounds = 5
+	// minResponseVariation is the least response times vary by, relative to their average, when a
+	// person is answering. Scripts that wait a fixed time before answering vary by less.
+	minResponseVariation = 0.05
+)
+
+// CheatReason is why a response looks like it didn't come from a person
+type CheatReason string
+
+const (
+	// TooFast responses were correct faster than a person could answer
+	TooFast = CheatReason("TOO_FAST")
+	// TooConsistent responses were correct in almost exactly the same time round after round
+	TooConsistent = CheatReason("TOO_CONSISTENT")
+)
+
+// FlaggedGame is the evidence of cheating found in a game, kept after the game has finished so
+// it can be reviewed. The JSON metadata is for converting this struct into a DynamoDB item.
+type FlaggedGame struct {
+	GameId    string      `json:"game_id"`
+	Flags     []CheatFlag `json:"flags"`
+	ExpiresAt int64       `json:"expires_at"`
+}
+
+// CheatFlag is one suspicious response
+type CheatFlag struct {
+	ConnectionId string      `json:"connection_id"`
+	PlayerId     string      `json:"player_id"`
+	Name         string      `json:"name"`
+	Round        int         `json:"round"`
+	Reason       CheatReason `json:"reason"`
+	Evidence     string      `json:"evidence"`
+	// How long the player took to respond, and the speed points they lost for it, if any
+	ResponseMillis    int64     `json:"response_millis"`
+	SpeedPointsVoided bool      `json:"speed_points_voided"`
+	FlaggedAt         time.Time `json:"flagged_at"`
+}
+
+// DetectCheating checks whether the response looks like it came from a script. Only correct
+// answers are checked, along with the player's recent correct response times. Returns false if
+// nothing looks wrong.
+func DetectCheating(player Player, response ScoredResponse) (CheatReason, string, bool) {
+	if !response.IsCorrect() {
+		return "", "", false
+	}
+	if response.Elapsed < HumanResponseThreshold {
+		return TooFast, fmt.Sprintf("answered correctly in %dms, faster than the %dms a person needs",
+			response.Elapsed.Milliseconds(), HumanResponseThreshold.Milliseconds()), true
+	}
+
+	recent := make([]int64, 0, len(player.CorrectResponseMillis)+1)
+	recent = append(recent, player.CorrectResponseMillis...)
+	recent = append(recent, response.Elapsed.Milliseconds())
+	if len(recent) < consistencyRounds {
+		return "", "", false
+	}
+	recent = recent[len(recent)-consistencyRounds:]
+	if variation := relativeVariation(recent); variation < minResponseVariation {
+		return TooConsistent, fmt.Sprintf("last %d correct answers took %v ms, varying by only %.1f%%",
+			consistencyRounds, recent, variation*100), true
+	}
+	return "", "", false
+}
+
+// relativeVariation returns the standard deviation of the values relative to their mean
+func relativeVariation(values []int64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += float64(value)
+	}
+	mean := sum / float64(len(values))
+	if mean == 0 {
+		return 0
+	}
+
+	var squares float64
+	for _, value := range values {
+		squares += (float64(value) - mean) * (float64(value) - mean)
+	}
+	return math.Sqrt(squares/float64(len(values))) / mean
+}
+
+// NewCheatFlag records the evidence against the player's response in the current round
+func (game *Game) NewCheatFlag(player Player, response ScoredResponse, reason CheatReason, evidence string, now time.Time) CheatFlag {
+	return CheatFlag{
+		ConnectionId:      player.ConnectionId,
+		PlayerId:          player.PlayerId,
+		Name:              player.Name,
+		Round:             game.RoundNumber,
+		Reason:            reason,
+		Evidence:          evidence,
+		ResponseMillis:    response.Elapsed.Milliseconds(),
+		SpeedPointsVoided: response.NoSpeedPoints,
+		FlaggedAt:         now,
+	}
+}
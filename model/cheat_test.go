@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectCheating(t *testing.T) {
+	allowed := 10 * time.Second
+	correct := func(elapsed time.Duration) ScoredResponse {
+		return ScoredResponse{Accuracy: 1, Elapsed: elapsed, Allowed: allowed}
+	}
+
+	tests := []struct {
+		name     string
+		history  []int64
+		response ScoredResponse
+		expected CheatReason
+	}{
+		{"human", []int64{1800, 2500, 1200, 3100}, correct(2 * time.Second), ""},
+		{"too fast", nil, correct(100 * time.Millisecond), TooFast},
+		{"too fast but wrong", nil, ScoredResponse{Accuracy: 0, Elapsed: 100 * time.Millisecond, Allowed: allowed}, ""},
+		{"too consistent", []int64{1000, 1010, 995, 1005}, correct(1002 * time.Millisecond), TooConsistent},
+		{"not enough rounds", []int64{1000, 1010, 995}, correct(1002 * time.Millisecond), ""},
+	}
+	for _, test := range tests {
+		player := Player{CorrectResponseMillis: test.history}
+		reason, _, suspicious := DetectCheating(player, test.response)
+		if reason != test.expected || suspicious != (test.expected != "") {
+			t.Errorf("%s: got %q and expected %q", test.name, reason, test.expected)
+		}
+	}
+}
+
+func TestNoSpeedPoints(t *testing.T) {
+	response := ScoredResponse{Accuracy: 1, Elapsed: 0, Allowed: 10 * time.Second, NoSpeedPoints: true}
+	if got := (classicPolicy{}).Points(response); got != correctPoints {
+		t.Errorf("Got %d points and expected %d", got, correctPoints)
+	}
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"hash/fnv"
+	"sort"
+	"time"
+)
+
+// DailyChallengeRounds is how many questions are asked in a daily challenge
+const DailyChallengeRounds = 10
+
+// DailyDateFormat is the layout of the date a daily challenge is for
+const DailyDateFormat = "2006-01-02"
+
+// DailyChallenge is the challenge everyone playing on the same date plays. Every play asks the
+// same questions in the same order, and each player can play it once. The JSON metadata is for
+// converting this struct into a DynamoDB item.
+type DailyChallenge struct {
+	Date string `json:"daily_date"`
+	Seed int64  `json:"seed"`
+	// The version of the corpus the questions are picked from, pinned by the first play
+	WordsVersion string `json:"words_version"`
+	// The ids of the players who have started the challenge
+	PlayerIds []string      `json:"player_ids"`
+	Results   []DailyResult `json:"results"`
+	ExpiresAt int64         `json:"expires_at"`
+}
+
+// DailyResult is how a player did in a daily challenge
+type DailyResult struct {
+	PlayerId            string    `json:"player_id"`
+	Name                string    `json:"name"`
+	Icon                string    `json:"icon"`
+	Points              int       `json:"points"`
+	CorrectAnswers      int       `json:"correct_answers"`
+	TotalResponseMillis int64     `json:"total_response_millis"`
+	FinishedAt          time.Time `json:"finished_at"`
+}
+
+// DailyDate returns the date of the daily challenge being played at the time. Days start at
+// midnight UTC, so everyone plays the same challenge at the same time.
+func DailyDate(now time.Time) string {
+	return now.UTC().Format(DailyDateFormat)
+}
+
+// DailySeed returns the seed the questions of a date's challenge are picked with
+func DailySeed(date string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(date))
+	return int64(hash.Sum64())
+}
+
+// NewDailyResult records how the player did in their play of the challenge
+func NewDailyResult(player Player, finishedAt time.Time) DailyResult {
+	return DailyResult{
+		PlayerId:            player.PlayerId,
+		Name:                player.Name,
+		Icon:                player.Icon,
+		Points:              player.Points,
+		CorrectAnswers:      player.CorrectAnswers,
+		TotalResponseMillis: player.TotalResponseMillis,
+		FinishedAt:          finishedAt,
+	}
+}
+
+// Leaderboard ranks the results by points, with the fastest player first when points are the
+// same. Players who can't be separated share a position.
+func (challenge *DailyChallenge) Leaderboard() DailyLeaderboard {
+	results := make([]DailyResult, len(challenge.Results))
+	copy(results, challenge.Results)
+	sort.SliceStable(results, func(i, j int) bool {
+		return compareDailyResults(results[i], results[j]) < 0
+	})
+
+	rankings := make([]DailyRanking, len(results))
+	for i, result := range results {
+		rankings[i] = DailyRanking{
+			Position:            i + 1,
+			Name:                result.Name,
+			Icon:                result.Icon,
+			Points:              result.Points,
+			CorrectAnswers:      result.CorrectAnswers,
+			TotalResponseMillis: result.TotalResponseMillis,
+		}
+		if i > 0 && compareDailyResults(results[i-1], result) == 0 {
+			rankings[i].Position = rankings[i-1].Position
+		}
+	}
+	return DailyLeaderboard{
+		Date:     challenge.Date,
+		Rankings: rankings,
+	}
+}
+
+// compareDailyResults returns a negative number if the first result ranks ahead of the second,
+// a positive number if it ranks behind, and zero if they are tied
+func compareDailyResults(result, other DailyResult) int {
+	if result.Points != other.Points {
+		return other.Points - result.Points
+	}
+	switch {
+	case result.TotalResponseMillis < other.TotalResponseMillis:
+		return -1
+	case result.TotalResponseMillis > other.TotalResponseMillis:
+		return 1
+	}
+	return 0
+}
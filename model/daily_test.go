@@ -0,0 +1,68 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailySeed(t *testing.T) {
+	if DailySeed("2020-05-01") != DailySeed("2020-05-01") {
+		t.Errorf("Got different seeds for the same date")
+	}
+	if DailySeed("2020-05-01") == DailySeed("2020-05-02") {
+		t.Errorf("Got the same seed for different dates")
+	}
+
+	evening := time.Date(2020, 5, 1, 23, 0, 0, 0, time.FixedZone("", -2*60*60))
+	if got := DailyDate(evening); got != "2020-05-02" {
+		t.Errorf("Got date %s and expected %s", got, "2020-05-02")
+	}
+}
+
+func TestGame_RoundRand(t *testing.T) {
+	words := Words{
+		Word{Word: "one", WordType: "noun"},
+		Word{Word: "two", WordType: "noun"},
+		Word{Word: "three", WordType: "noun"},
+		Word{Word: "quick", WordType: "adjective"},
+		Word{Word: "slow", WordType: "adjective"},
+		Word{Word: "bright", WordType: "adjective"},
+	}
+	pick := func(game Game) (string, Words) {
+		rng := game.RoundRand()
+		wordsByType := words.GroupByType()
+		wordType := PickRandomTypeFrom(rng, wordsByType, 2)
+		return wordType, wordsByType[wordType].PickRandomWords(rng, 2)
+	}
+
+	for round := 1; round <= DailyChallengeRounds; round++ {
+		game := Game{Seed: DailySeed("2020-05-01"), RoundNumber: round}
+		firstType, firstWords := pick(game)
+		secondType, secondWords := pick(game)
+		if firstType != secondType || firstWords[0].Word != secondWords[0].Word || firstWords[1].Word != secondWords[1].Word {
+			t.Errorf("Got different questions in round %d of plays with the same seed", round)
+		}
+	}
+}
+
+func TestDailyChallenge_Leaderboard(t *testing.T) {
+	challenge := DailyChallenge{
+		Date: "2020-05-01",
+		Results: []DailyResult{
+			{Name: "slow", Points: 300, TotalResponseMillis: 9000},
+			{Name: "best", Points: 400, TotalResponseMillis: 9000},
+			{Name: "fast", Points: 300, TotalResponseMillis: 5000},
+			{Name: "twin", Points: 300, TotalResponseMillis: 9000},
+		},
+	}
+
+	got := challenge.Leaderboard().Rankings
+	expectedNames := []string{"best", "fast", "slow", "twin"}
+	expectedPositions := []int{1, 2, 3, 3}
+	for i, ranking := range got {
+		if ranking.Name != expectedNames[i] || ranking.Position != expectedPositions[i] {
+			t.Errorf("Got %s in position %d and expected %s in position %d",
+				ranking.Name, ranking.Position, expectedNames[i], expectedPositions[i])
+		}
+	}
+}
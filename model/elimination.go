@@ -0,0 +1,89 @@
+package model
+
+// EliminationRule decides who is knocked out of the race after each round
+type EliminationRule string
+
+const (
+	// NoElimination keeps everyone racing until the target score is reached
+	NoElimination = EliminationRule("NONE")
+	// EliminateSlowest knocks out the player who did worst in the round. Players who didn't
+	// answer go first, then those with the fewest points for the round, then the slowest.
+	EliminateSlowest = EliminationRule("SLOWEST")
+	// EliminateLowestScore knocks out the player with the lowest score, then the slowest
+	EliminateLowestScore = EliminationRule("LOWEST_SCORE")
+)
+
+// IsValid returns true if the elimination rule is known
+func (rule EliminationRule) IsValid() bool {
+	switch rule {
+	case NoElimination, EliminateSlowest, EliminateLowestScore:
+		return true
+	}
+	return false
+}
+
+// IsEliminationRace returns true if players are knocked out until one remains
+func (game *Game) IsEliminationRace() bool {
+	return game.Elimination != "" && game.Elimination != NoElimination
+}
+
+// Eliminate knocks out the player who did worst in the round just finished. Returns the player
+// eliminated, or nil if there weren't enough players left racing.
+func (game *Game) Eliminate(players Players) *Player {
+	if !game.IsEliminationRace() || game.SuddenDeath || players.NumRacing() < 2 {
+		return nil
+	}
+
+	var loser *Player
+	for _, p := range players {
+		if !p.IsRacing() {
+			continue
+		}
+		if loser == nil || game.didWorse(p, loser) {
+			loser = p
+		}
+	}
+
+	loser.Eliminated = true
+	loser.EliminatedInRound = game.RoundNumber
+	return loser
+}
+
+// didWorse returns true if the first player did worse than the second under the game's rule
+func (game *Game) didWorse(p, other *Player) bool {
+	if p.Responded != other.Responded {
+		return !p.Responded
+	}
+	if game.Elimination == EliminateLowestScore && p.Points != other.Points {
+		return p.Points < other.Points
+	}
+	if game.Elimination == EliminateSlowest && p.RoundPoints != other.RoundPoints {
+		return p.RoundPoints < other.RoundPoints
+	}
+	return p.ResponseMillis > other.ResponseMillis
+}
+
+// IsRacing returns true if the player is still connected, hasn't been eliminated and isn't
+// watching a sudden-death round
+func (p Player) IsRacing() bool {
+	return p.Active && !p.Eliminated && !p.Spectating
+}
+
+// NumRacing returns the number of players still racing
+func (players Players) NumRacing() int {
+	racing := 0
+	for _, p := range players {
+		if p.IsRacing() {
+			racing++
+		}
+	}
+	return racing
+}
+
+// eliminatedLater returns true if the first player lasted longer than the second
+func eliminatedLater(p, other *Player) bool {
+	if !p.Eliminated || !other.Eliminated {
+		return !p.Eliminated
+	}
+	return p.EliminatedInRound > other.EliminatedInRound
+}
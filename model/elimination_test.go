@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEliminate(t *testing.T) {
+	newPlayers := func() Players {
+		return Players{
+			{Name: "Ann", Active: true, Responded: true, Points: 300, RoundPoints: 100, ResponseMillis: 4000},
+			{Name: "Bob", Active: true, Responded: true, Points: 200, RoundPoints: 140, ResponseMillis: 1000},
+			{Name: "Cat", Active: true, Responded: true, Points: 250, RoundPoints: 100, ResponseMillis: 2000},
+			{Name: "Dan", Active: false, Points: 0},
+		}
+	}
+	tests := []struct {
+		rule     EliminationRule
+		expected string
+	}{
+		{EliminateSlowest, "Ann"},
+		{EliminateLowestScore, "Bob"},
+	}
+
+	for _, test := range tests {
+		game := Game{Elimination: test.rule, RoundNumber: 4}
+		players := newPlayers()
+		eliminated := game.Eliminate(players)
+		if eliminated == nil || eliminated.Name != test.expected {
+			t.Errorf("%s: got %v and expected %s", test.rule, eliminated, test.expected)
+			continue
+		}
+		if !eliminated.Eliminated || eliminated.EliminatedInRound != 4 || players.NumRacing() != 2 {
+			t.Errorf("%s: player wasn't knocked out: %+v", test.rule, eliminated)
+		}
+	}
+
+	if (&Game{Elimination: NoElimination}).Eliminate(newPlayers()) != nil {
+		t.Error("Expected nobody to be eliminated without elimination")
+	}
+}
+
+func TestEliminationRace(t *testing.T) {
+	game := Game{Elimination: EliminateSlowest, TargetScore: 100}
+	players := Players{
+		{Name: "Ann", Active: true, Responded: true, Points: 10, ResponseMillis: 1000},
+		{Name: "Bob", Active: true, Responded: false, Points: 500},
+	}
+
+	if game.IsOver(players, time.Now()) {
+		t.Error("The race isn't over until one player is left")
+	}
+	game.RoundNumber = 1
+	game.Eliminate(players)
+	if !game.IsOver(players, time.Now()) {
+		t.Error("The race is over when one player is left")
+	}
+
+	rankings := players.Rankings(FastestTieBreaker)
+	if rankings[0].Name != "Ann" || rankings[1].Name != "Bob" || rankings[1].EliminatedInRound != 1 {
+		t.Errorf("Got rankings %+v", rankings)
+	}
+}
+
+func TestSprint(t *testing.T) {
+	start := time.Now()
+	players := Players{{Name: "Ann", Active: true, Points: 1000}}
+
+	game := Game{TargetScore: 500, RoundLimit: 5, RoundNumber: 4}
+	if game.IsOver(players, start) {
+		t.Error("A sprint isn't won by reaching the target score")
+	}
+	game.RoundNumber = 5
+	if !game.IsOver(players, start) {
+		t.Error("A sprint is over after the round limit")
+	}
+
+	game = Game{TimeLimitSeconds: 60, StartedAt: start}
+	if game.IsOver(players, start.Add(59*time.Second)) || !game.IsOver(players, start.Add(time.Minute)) {
+		t.Error("A sprint is over after the time limit")
+	}
+}
@@ -1,6 +1,9 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
 	"time"
 )
 
@@ -13,9 +16,73 @@ type Game struct {
 	MaxPlayerCount     int       `json:"max_player_count"`
 	GameState          GameState `json:"game_state"`
 	CorrectAnswer      int       `json:"correct_answer"`
+	CustomWords        bool      `json:"custom_words"`
+	WordsVersion       string    `json:"words_version"`
 	RoundStartTime     time.Time `json:"round_start_time"`
 	CreatedAt          time.Time `json:"created_at"`
 	ExpiresAt          int64     `json:"expires_at"`
+	// The language of the words to guess
+	Language string `json:"language"`
+	// The language words are translated to in translation rounds
+	TranslateTo string `json:"translate_to"`
+	// Each round asks one of these kinds of question
+	QuestionModes []QuestionMode `json:"question_modes"`
+	// The kind of question asked in the current round
+	RoundMode QuestionMode `json:"round_mode"`
+	// The word to type in type-in rounds
+	CorrectWord string `json:"correct_word"`
+	// The word the current round's question is about
+	RoundWord string `json:"round_word"`
+	// Show the definition of the word in part of speech rounds
+	PartOfSpeechHint bool `json:"part_of_speech_hint"`
+	// How points are awarded
+	ScoringPolicy ScoringPolicyName `json:"scoring_policy"`
+	// The number of the current round, starting from 1
+	RoundNumber int `json:"round_number"`
+	// The last round someone answered correctly, and who answered first
+	FirstCorrectRound int    `json:"first_correct_round"`
+	FirstCorrectBy    string `json:"first_correct_by"`
+	// Bonus points for each correct answer in a row, and the most bonus points for one answer
+	StreakBonus    int `json:"streak_bonus"`
+	MaxStreakBonus int `json:"max_streak_bonus"`
+	// The teams players race in. There are no teams when empty.
+	Teams []string `json:"teams"`
+	// How the points of a team's members make up the team's score
+	TeamScoring TeamScoring `json:"team_scoring"`
+	// Who is knocked out after each round, if anyone
+	Elimination EliminationRule `json:"elimination"`
+	// Sprints end after a number of rounds or amount of time instead of at the target score.
+	// Zero means no limit.
+	RoundLimit       int `json:"round_limit"`
+	TimeLimitSeconds int `json:"time_limit_seconds"`
+	// When the first round was about to start
+	StartedAt time.Time `json:"started_at"`
+	// How players who finish with the same score are ordered
+	TieBreaker TieBreaker `json:"tie_breaker"`
+	// True if the current round is a sudden-death round between the players tied for first
+	SuddenDeath bool `json:"sudden_death"`
+	// Show each player the options in their own order
+	ShuffleOptions bool `json:"shuffle_options"`
+	// Keep each player's result to themselves until everyone has responded, so nobody can pass
+	// on the answer while the round is open
+	WithholdResults bool `json:"withhold_results"`
+	// Responses that look like cheating win no points for speed
+	ZeroFlaggedSpeedPoints bool `json:"zero_flagged_speed_points"`
+	// The connection id of the player the game was created for, who can change its rules. Games
+	// created for a tournament or a single player have no creator.
+	CreatorConnectionId string `json:"creator_connection_id,omitempty"`
+	// The tournament this game is a heat of, if any
+	TournamentId string `json:"tournament_id,omitempty"`
+	// Questions are picked using this seed, so games with the same seed and words ask the same
+	// questions
+	Seed int64 `json:"seed"`
+	// The date of the daily challenge this game is a play of, if any
+	DailyDate string `json:"daily_date,omitempty"`
+	// True if the game is a player practicing on their own, and the words asked so far
+	Practice       bool            `json:"practice,omitempty"`
+	PracticeRounds []PracticeRound `json:"practice_rounds,omitempty"`
+	// The id of the player whose missed words a review game asks
+	ReviewFor string `json:"review_for,omitempty"`
 }
 
 type GameState string
@@ -26,26 +93,246 @@ const (
 	Finished   = GameState("FINISHED")
 )
 
-func (game *Game) CalculatePoints(submittedAnswer int, timeReceived time.Time) int {
-	elapsedDuration := timeReceived.Sub(game.RoundStartTime)
-	durationPerQuestion := time.Duration(game.SecondsPerQuestion) * time.Second
+// RoundRand returns the source of randomness for picking the current round's question. It only
+// depends on the game's seed and the round number, so a round can be replayed exactly.
+func (game *Game) RoundRand() *rand.Rand {
+	return rand.New(rand.NewSource(game.Seed + int64(game.RoundNumber)))
+}
+
+// IsCreator returns true if the game was created for the player
+func (game *Game) IsCreator(player Player) bool {
+	return game.CreatorConnectionId != "" && game.CreatorConnectionId == player.ConnectionId
+}
+
+// IsDailyChallenge returns true if the game is a play of a daily challenge
+func (game *Game) IsDailyChallenge() bool {
+	return game.DailyDate != ""
+}
 
-	// Player took longer than allowed time - no points!
-	if elapsedDuration > durationPerQuestion {
-		return 0
+// ScoreResponse works out how the player's response went, taking their streak and power-ups into
+// account. Time is measured from when the question was delivered to the player. Whether it was
+// the first correct answer is left for the caller to fill in.
+func (game *Game) ScoreResponse(player Player, response PlayerResponse, timeReceived time.Time) ScoredResponse {
+	return ScoredResponse{
+		Accuracy:     game.Accuracy(response),
+		Elapsed:      timeReceived.Sub(game.QuestionDeliveredAt(player, response, timeReceived)),
+		Allowed:      time.Duration(player.SecondsAllowed(game.SecondsPerQuestion)) * time.Second,
+		Streak:       player.Streak,
+		DoublePoints: player.HasRoundPowerUp(DoublePoints),
+	}
+}
+
+// CalculatePoints returns the points for a response using the game's scoring policy. The streak
+// bonus isn't added under the streak policy, which already rewards streaks.
+func (game *Game) CalculatePoints(response ScoredResponse) int {
+	policy, err := ScoringPolicyByName(game.ScoringPolicy)
+	if err != nil {
+		// Rules are validated before they are applied, so this should never happen
+		policy = classicPolicy{}
+	}
+	points := policy.Points(response)
+	if response.IsCorrect() && game.ScoringPolicy != StreakScoring {
+		points += game.StreakBonusFor(response.Streak)
+	}
+	if response.DoublePoints {
+		points *= 2
+	}
+	return points
+}
+
+// StreakBonusFor returns the bonus for a correct answer after the given number of correct
+// answers in a row. The bonus escalates with the streak, up to the maximum.
+func (game *Game) StreakBonusFor(streak int) int {
+	bonus := game.StreakBonus * streak
+	if bonus > game.MaxStreakBonus {
+		bonus = game.MaxStreakBonus
+	}
+	return bonus
+}
+
+// ScoringDescription explains the game's scoring policy to players
+func (game *Game) ScoringDescription() string {
+	policy, err := ScoringPolicyByName(game.ScoringPolicy)
+	if err != nil {
+		return ""
+	}
+	if game.StreakBonus > 0 && game.MaxStreakBonus > 0 && game.ScoringPolicy != StreakScoring {
+		return fmt.Sprintf("%s Win %d more points for each correct answer in a row, up to %d.",
+			policy.Description(), game.StreakBonus, game.MaxStreakBonus)
 	}
+	return policy.Description()
+}
+
+// Accuracy returns how close the response is to the correct answer, from 0 (wrong) to 1 (right)
+func (game *Game) Accuracy(response PlayerResponse) float64 {
+	if game.RoundMode == TypeInMode {
+		return JudgeTypedAnswer(response.Text, game.CorrectWord)
+	}
+
+	if response.Response == game.CorrectAnswer {
+		return 1
+	}
+	return 0
+}
+
+// PlayerResult tells a player how their response this round went, with the correct answer as
+// one of the options they were shown
+func (game *Game) PlayerResult(player Player) PlayerResult {
+	return PlayerResult{
+		Correct:       player.RoundAccuracy == 1,
+		CorrectAnswer: player.ShownOption(game.CorrectAnswer),
+		CorrectWord:   game.CorrectWord,
+		NearMiss:      player.RoundAccuracy > 0 && player.RoundAccuracy < 1,
+		PowerUpEarned: player.PowerUpEarned,
+	}
+}
+
+// IsSprint returns true if the game ends after a number of rounds or amount of time
+func (game *Game) IsSprint() bool {
+	return game.RoundLimit > 0 || game.TimeLimitSeconds > 0
+}
+
+// IsOver returns true if the game has finished. Elimination races end when one player is left
+// racing, and sprints when the round or time limit is reached. The round in progress is always
+// finished. Other races end when the target score is reached.
+func (game *Game) IsOver(players Players, now time.Time) bool {
+	if game.IsEliminationRace() && players.NumRacing() <= 1 {
+		return true
+	}
+	if game.IsSprint() {
+		if game.RoundLimit > 0 && game.RoundNumber >= game.RoundLimit {
+			return true
+		}
+		timeLimit := time.Duration(game.TimeLimitSeconds) * time.Second
+		return game.TimeLimitSeconds > 0 && now.Sub(game.StartedAt) >= timeLimit
+	}
+	if game.IsEliminationRace() {
+		return false
+	}
+	return game.LeadingScore(players) >= game.TargetScore
+}
 
-	// Points for correct answer
-	correctPoints := 0
-	if submittedAnswer == game.CorrectAnswer {
-		correctPoints += 100
+// ApplyRules changes how the game is played. Rules that are not provided are left unchanged.
+func (game *Game) ApplyRules(rules SetRules) error {
+	if rules.Language != "" {
+		if !IsSupportedLanguage(rules.Language) {
+			return fmt.Errorf("unsupported language: %s", rules.Language)
+		}
+		game.Language = rules.Language
 	}
 
-	// Points for answering quickly
-	timePoints := int(50 * (durationPerQuestion - elapsedDuration) / durationPerQuestion)
-	if timePoints < 0 {
-		timePoints = 0
+	if rules.TranslateTo != "" {
+		if !IsSupportedLanguage(rules.TranslateTo) {
+			return fmt.Errorf("unsupported language: %s", rules.TranslateTo)
+		}
+		game.TranslateTo = rules.TranslateTo
 	}
 
-	return correctPoints + timePoints
+	if len(rules.QuestionModes) > 0 {
+		for _, mode := range rules.QuestionModes {
+			if !mode.IsValid() {
+				return fmt.Errorf("unknown question mode: %s", mode)
+			}
+		}
+		game.QuestionModes = rules.QuestionModes
+	}
+
+	if rules.PartOfSpeechHint != nil {
+		game.PartOfSpeechHint = *rules.PartOfSpeechHint
+	}
+
+	if rules.ScoringPolicy != "" {
+		if _, err := ScoringPolicyByName(rules.ScoringPolicy); err != nil {
+			return err
+		}
+		game.ScoringPolicy = rules.ScoringPolicy
+	}
+
+	if rules.StreakBonus != nil {
+		if *rules.StreakBonus < 0 {
+			return errors.New("the streak bonus can't be negative")
+		}
+		game.StreakBonus = *rules.StreakBonus
+	}
+	if rules.MaxStreakBonus != nil {
+		if *rules.MaxStreakBonus < 0 {
+			return errors.New("the maximum streak bonus can't be negative")
+		}
+		game.MaxStreakBonus = *rules.MaxStreakBonus
+	}
+
+	if rules.Teams != nil {
+		if err := validateTeams(rules.Teams); err != nil {
+			return err
+		}
+		game.Teams = rules.Teams
+	}
+	if rules.TeamScoring != "" {
+		if !rules.TeamScoring.IsValid() {
+			return fmt.Errorf("unknown team scoring: %s", rules.TeamScoring)
+		}
+		game.TeamScoring = rules.TeamScoring
+	}
+
+	if rules.Elimination != "" {
+		if !rules.Elimination.IsValid() {
+			return fmt.Errorf("unknown elimination rule: %s", rules.Elimination)
+		}
+		game.Elimination = rules.Elimination
+	}
+
+	if rules.RoundLimit != nil {
+		if *rules.RoundLimit < 0 {
+			return errors.New("the round limit can't be negative")
+		}
+		game.RoundLimit = *rules.RoundLimit
+	}
+	if rules.TimeLimitSeconds != nil {
+		if *rules.TimeLimitSeconds < 0 {
+			return errors.New("the time limit can't be negative")
+		}
+		game.TimeLimitSeconds = *rules.TimeLimitSeconds
+	}
+
+	if rules.ShuffleOptions != nil {
+		game.ShuffleOptions = *rules.ShuffleOptions
+	}
+
+	if rules.WithholdResults != nil {
+		game.WithholdResults = *rules.WithholdResults
+	}
+
+	if rules.ZeroFlaggedSpeedPoints != nil {
+		game.ZeroFlaggedSpeedPoints = *rules.ZeroFlaggedSpeedPoints
+	}
+
+	if rules.TieBreaker != "" {
+		if !rules.TieBreaker.IsValid() {
+			return fmt.Errorf("unknown tie-breaker: %s", rules.TieBreaker)
+		}
+		game.TieBreaker = rules.TieBreaker
+	}
+
+	if game.HasQuestionMode(TranslationMode) && game.TranslateTo == game.Language {
+		return errors.New("translation rounds need a language to translate to")
+	}
+	return nil
+}
+
+// HasQuestionMode returns true if rounds of the game can ask the kind of question
+func (game *Game) HasQuestionMode(mode QuestionMode) bool {
+	for _, questionMode := range game.QuestionModes {
+		if questionMode == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// PickRandomQuestionMode returns one of the kinds of question this game asks
+func (game *Game) PickRandomQuestionMode(rng *rand.Rand) QuestionMode {
+	if len(game.QuestionModes) == 0 {
+		return DefinitionMode
+	}
+	return game.QuestionModes[rng.Intn(len(game.QuestionModes))]
 }
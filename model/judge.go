@@ -0,0 +1,89 @@
+package model
+
+import (
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// NearMissAccuracy is the accuracy of a typed answer that is close to, but not exactly, the word
+const NearMissAccuracy = 0.5
+
+// JudgeTypedAnswer returns how close a typed answer is to the word, from 0 (wrong) to 1 (right).
+// Case, accents and surrounding spaces are ignored. Answers within a few typos of the word are
+// a near miss.
+func JudgeTypedAnswer(typed string, word string) float64 {
+	normalisedTyped := NormaliseAnswer(typed)
+	normalisedWord := NormaliseAnswer(word)
+
+	if normalisedTyped == "" {
+		return 0
+	}
+	if normalisedTyped == normalisedWord {
+		return 1
+	}
+	if EditDistance(normalisedTyped, normalisedWord) <= allowedTypos(normalisedWord) {
+		return NearMissAccuracy
+	}
+	return 0
+}
+
+// allowedTypos is how many edits a typed answer can be from the word and still be a near miss.
+// Short words have to be spelt exactly, otherwise any short word would be a near miss.
+func allowedTypos(word string) int {
+	return len([]rune(word)) / 4
+}
+
+// NormaliseAnswer lower cases an answer, strips accents and collapses spaces
+func NormaliseAnswer(answer string) string {
+	stripAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(stripAccents, answer)
+	if err != nil {
+		stripped = answer
+	}
+	return strings.Join(strings.Fields(strings.ToLower(stripped)), " ")
+}
+
+// EditDistance is the Levenshtein distance between two strings: the number of single character
+// insertions, deletions or substitutions to turn one into the other
+func EditDistance(a string, b string) int {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+
+	// Only the previous row of the distance matrix is needed to calculate the next one
+	previousRow := make([]int, len(bRunes)+1)
+	currentRow := make([]int, len(bRunes)+1)
+	for j := range previousRow {
+		previousRow[j] = j
+	}
+
+	for i := 1; i <= len(aRunes); i++ {
+		currentRow[0] = i
+		for j := 1; j <= len(bRunes); j++ {
+			substitutionCost := 1
+			if aRunes[i-1] == bRunes[j-1] {
+				substitutionCost = 0
+			}
+			currentRow[j] = smallest(
+				previousRow[j]+1,
+				currentRow[j-1]+1,
+				previousRow[j-1]+substitutionCost,
+			)
+		}
+		previousRow, currentRow = currentRow, previousRow
+	}
+
+	return previousRow[len(bRunes)]
+}
+
+func smallest(values ...int) int {
+	smallestValue := values[0]
+	for _, value := range values[1:] {
+		if value < smallestValue {
+			smallestValue = value
+		}
+	}
+	return smallestValue
+}
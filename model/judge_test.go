@@ -0,0 +1,32 @@
+package model
+
+import "testing"
+
+func TestJudgeTypedAnswer(t *testing.T) {
+	tests := []struct {
+		typed    string
+		word     string
+		expected float64
+	}{
+		{"Serendipity", "serendipity", 1},
+		{"  cafe ", "café", 1},
+		{"serendipty", "serendipity", NearMissAccuracy},
+		{"serenity", "serendipity", 0},
+		{"cat", "cot", 0},
+		{"", "cot", 0},
+	}
+
+	for _, test := range tests {
+		got := JudgeTypedAnswer(test.typed, test.word)
+		if got != test.expected {
+			t.Errorf("Typed %q for %q: got %v and expected %v", test.typed, test.word, got, test.expected)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	got := EditDistance("kitten", "sitting")
+	if got != 3 {
+		t.Errorf("Got %d and expected %d", got, 3)
+	}
+}
@@ -0,0 +1,85 @@
+package model
+
+import "strings"
+
+// DefaultLanguage is the language of words that don't say what language they are in
+const DefaultLanguage = "en"
+
+// wordTypeNames are the names of each word type in each supported language, keyed by the
+// English name used throughout the corpus
+var wordTypeNames = map[string]map[string]string{
+	"en": {
+		"noun":      "noun",
+		"adjective": "adjective",
+		"verb":      "verb",
+		"adverb":    "adverb",
+	},
+	"es": {
+		"noun":      "sustantivo",
+		"adjective": "adjetivo",
+		"verb":      "verbo",
+		"adverb":    "adverbio",
+	},
+	"fr": {
+		"noun":      "nom",
+		"adjective": "adjectif",
+		"verb":      "verbe",
+		"adverb":    "adverbe",
+	},
+}
+
+// IsSupportedLanguage returns true if games can be played in the language
+func IsSupportedLanguage(language string) bool {
+	_, present := wordTypeNames[language]
+	return present
+}
+
+// LocalizedWordType returns the name of a word type in the given language. The English name is
+// returned if there is no translation.
+func LocalizedWordType(language string, wordType string) string {
+	if name, present := wordTypeNames[language][wordType]; present {
+		return name
+	}
+	return wordType
+}
+
+// CanonicalWordType returns the English name of a word type given in any supported language
+func CanonicalWordType(language string, name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for wordType, localizedName := range wordTypeNames[language] {
+		if localizedName == name {
+			return wordType
+		}
+	}
+	return name
+}
+
+// LanguageOrDefault returns the language of the word
+func (d Word) LanguageOrDefault() string {
+	if d.Language == "" {
+		return DefaultLanguage
+	}
+	return d.Language
+}
+
+// FilterByLanguage returns the words in the given language
+func (words Words) FilterByLanguage(language string) Words {
+	filteredWords := make(Words, 0, len(words))
+	for _, word := range words {
+		if word.LanguageOrDefault() == language {
+			filteredWords = append(filteredWords, word)
+		}
+	}
+	return filteredWords
+}
+
+// FilterByTranslation returns the words that have a translation into the given language
+func (words Words) FilterByTranslation(language string) Words {
+	filteredWords := make(Words, 0, len(words))
+	for _, word := range words {
+		if word.Translations[language] != "" {
+			filteredWords = append(filteredWords, word)
+		}
+	}
+	return filteredWords
+}
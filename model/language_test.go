@@ -0,0 +1,39 @@
+package model
+
+import "testing"
+
+func TestCanonicalWordType(t *testing.T) {
+	got := CanonicalWordType("es", " Sustantivo ")
+	if got != "noun" {
+		t.Errorf("Got %s and expected %s", got, "noun")
+	}
+	got = LocalizedWordType("fr", "adverb")
+	if got != "adverbe" {
+		t.Errorf("Got %s and expected %s", got, "adverbe")
+	}
+}
+
+func TestWords_FilterByLanguage(t *testing.T) {
+	words := Words{
+		Word{Word: "dog"},
+		Word{Word: "perro", Language: "es"},
+		Word{Word: "chien", Language: "fr"},
+	}
+	got := words.FilterByLanguage(DefaultLanguage)
+	if len(got) != 1 || got[0].Word != "dog" {
+		t.Errorf("Got %v and expected only dog", got)
+	}
+}
+
+func TestGame_ApplyRules_TranslationNeedsAnotherLanguage(t *testing.T) {
+	game := Game{Language: "en", TranslateTo: "en"}
+	err := game.ApplyRules(SetRules{QuestionModes: []QuestionMode{TranslationMode}})
+	if err == nil {
+		t.Errorf("Expected an error translating to the same language")
+	}
+
+	err = game.ApplyRules(SetRules{Language: "es"})
+	if err != nil {
+		t.Errorf("Got error %s", err)
+	}
+}
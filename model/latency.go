@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// MaxLatencyCompensation is the most time a player can be given back for their question taking
+// a while to reach them. It stops clients claiming more time to answer than delivery could have
+// taken.
+const MaxLatencyCompensation = 500 * time.Millisecond
+
+// QuestionDeliveredAt returns when the player's time to answer started. Questions are sent to
+// players one by one, so each player's time starts when their own question was sent rather than
+// when the round started. If the client says how long the player took to answer, the rest of the
+// time since the question was sent was spent delivering the question and the response, and is
+// given back to the player up to MaxLatencyCompensation. Only durations measured by each clock
+// are compared, so the client's clock doesn't have to agree with the server's.
+func (game *Game) QuestionDeliveredAt(player Player, response PlayerResponse, timeReceived time.Time) time.Time {
+	// Without a record of when the question was sent, the client can't be checked
+	sentAt := player.QuestionSentAt
+	if sentAt.Before(game.RoundStartTime) || sentAt.After(timeReceived) {
+		return game.RoundStartTime
+	}
+
+	answered := time.Duration(response.AnswerMillis) * time.Millisecond
+	sinceSent := timeReceived.Sub(sentAt)
+	if answered <= 0 || answered > sinceSent {
+		return sentAt
+	}
+	delivery := sinceSent - answered
+	if delivery > MaxLatencyCompensation {
+		delivery = MaxLatencyCompensation
+	}
+	return sentAt.Add(delivery)
+}
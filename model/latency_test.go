@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGame_QuestionDeliveredAt(t *testing.T) {
+	roundStart := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	received := roundStart.Add(5 * time.Second)
+	game := Game{RoundStartTime: roundStart}
+	sentAt := roundStart.Add(time.Second)
+
+	tests := []struct {
+		name     string
+		sentAt   time.Time
+		response PlayerResponse
+		expected time.Time
+	}{
+		{"not recorded", time.Time{}, PlayerResponse{}, roundStart},
+		{"sent to player", sentAt, PlayerResponse{}, sentAt},
+		{"sent last round", roundStart.Add(-time.Minute), PlayerResponse{}, roundStart},
+		{"sent after response", received.Add(time.Second), PlayerResponse{}, roundStart},
+		{"answer time reported", sentAt, PlayerResponse{AnswerMillis: 3700}, sentAt.Add(300 * time.Millisecond)},
+		{"no delivery time", sentAt, PlayerResponse{AnswerMillis: 4000}, sentAt},
+		{"slow delivery", sentAt, PlayerResponse{AnswerMillis: 1000}, sentAt.Add(MaxLatencyCompensation)},
+		{"claims longer than possible", sentAt, PlayerResponse{AnswerMillis: 4500}, sentAt},
+		{"claims negative answer time", sentAt, PlayerResponse{AnswerMillis: -1000}, sentAt},
+		{"sending not recorded", time.Time{}, PlayerResponse{AnswerMillis: 4800}, roundStart},
+	}
+	for _, test := range tests {
+		player := Player{QuestionSentAt: test.sentAt}
+		got := game.QuestionDeliveredAt(player, test.response, received)
+		if !got.Equal(test.expected) {
+			t.Errorf("%s: got %v and expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+// The client's clock being hours out doesn't change anything, as it only reports a duration
+func TestGame_QuestionDeliveredAtIgnoresClockSkew(t *testing.T) {
+	roundStart := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	game := Game{RoundStartTime: roundStart}
+	player := Player{QuestionSentAt: roundStart.Add(time.Second)}
+	received := roundStart.Add(3 * time.Second)
+
+	clientReceived := roundStart.Add(3 * time.Hour)
+	clientAnswered := clientReceived.Add(1800 * time.Millisecond)
+	response := PlayerResponse{AnswerMillis: clientAnswered.Sub(clientReceived).Milliseconds()}
+
+	expected := player.QuestionSentAt.Add(200 * time.Millisecond)
+	if got := game.QuestionDeliveredAt(player, response, received); !got.Equal(expected) {
+		t.Errorf("Got %v and expected %v", got, expected)
+	}
+}
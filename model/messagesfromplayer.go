@@ -5,15 +5,93 @@ type MessageFromPlayer struct {
 	MessageType    string
 	NewPlayer      *NewPlayer      `json:",omitempty"`
 	PlayerResponse *PlayerResponse `json:",omitempty"`
+	UploadWords    *UploadWords    `json:",omitempty"`
+	SetRules       *SetRules       `json:",omitempty"`
+	UsePowerUp     *UsePowerUp     `json:",omitempty"`
+	JoinTournament *JoinTournament `json:",omitempty"`
 }
 
 // NewPlayer is sent from the player when they are ready to start playing
 type NewPlayer struct {
 	Name string
 	Icon string
+	// The team the player would like to join in team races
+	Team string `json:",omitempty"`
+	// The id the client keeps between connections, which identifies the player over time
+	PlayerId string `json:",omitempty"`
+	// Play today's daily challenge on your own instead of joining a game
+	DailyChallenge bool `json:",omitempty"`
+	// Practice on your own, starting straight away
+	Practice bool `json:",omitempty"`
+	// Practice on your own with the words you have missed before, which needs a player id
+	Review bool `json:",omitempty"`
 }
 
 // PlayerResponse is the response from the player
 type PlayerResponse struct {
+	// The option chosen in multiple choice rounds
 	Response int
+	// The word typed in type-in rounds
+	Text string `json:",omitempty"`
+	// Optional: how long the player took to answer by the client's clock, in milliseconds from
+	// when the client received the question
+	AnswerMillis int64 `json:",omitempty"`
+}
+
+// UploadWords is sent from the player who created a game to play with their own word list
+type UploadWords struct {
+	// Either "csv" or "json". Detected from the content if not provided.
+	Format  WordListFormat
+	Content string
+}
+
+// SetRules is sent from the player who created a game to change how it is played. Rules that
+// are not provided are left unchanged.
+type SetRules struct {
+	// The language of the words to guess, such as "en", "es" or "fr"
+	Language string
+	// The language words are translated to in translation rounds
+	TranslateTo string
+	// The kinds of question asked. One is picked at random each round.
+	QuestionModes []QuestionMode
+	// Show the definition of the word in part of speech rounds
+	PartOfSpeechHint *bool
+	// How points are awarded, such as "CLASSIC" or "STREAK"
+	ScoringPolicy ScoringPolicyName
+	// Bonus points for each correct answer in a row, and the most bonus points for one answer
+	StreakBonus    *int
+	MaxStreakBonus *int
+	// The names of the teams to race in. An empty list turns teams off.
+	Teams []string
+	// How a team's score is made up, either "SUM" or "AVERAGE"
+	TeamScoring TeamScoring
+	// Who is knocked out after each round: "NONE", "SLOWEST" or "LOWEST_SCORE"
+	Elimination EliminationRule
+	// End the game after this many rounds or seconds instead of at the target score. Zero
+	// removes the limit.
+	RoundLimit       *int
+	TimeLimitSeconds *int
+	// How players who finish with the same score are ordered: "NONE", "FASTEST" or
+	// "SUDDEN_DEATH"
+	TieBreaker TieBreaker
+	// Show each player the options in their own order. Off unless turned on.
+	ShuffleOptions *bool
+	// Send players their results when the round closes instead of as soon as they respond
+	WithholdResults *bool
+	// Win no points for speed on responses that look like cheating
+	ZeroFlaggedSpeedPoints *bool
+}
+
+// UsePowerUp is sent from a player to spend a power-up on the next round
+type UsePowerUp struct {
+	PowerUp PowerUp
+}
+
+// JoinTournament is sent from a player to register for a tournament, or to watch it
+type JoinTournament struct {
+	TournamentId string
+	Name         string
+	Icon         string
+	// True to follow the bracket without playing
+	Spectate bool `json:",omitempty"`
 }
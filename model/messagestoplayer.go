@@ -2,19 +2,35 @@ package model
 
 type MessageToPlayer struct {
 	//PlayerDetailsReq *PlayerDetailsReq `json:",omitempty"`
-	Welcome         *Welcome         `json:",omitempty"`
-	AboutToStart    *AboutToStart    `json:",omitempty"`
-	PresentQuestion *PresentQuestion `json:",omitempty"`
-	PlayerResult    *PlayerResult    `json:",omitempty"`
-	RoundSummary    *RoundSummary    `json:",omitempty"`
-	Summary         *Summary         `json:",omitempty"`
-	//Error            *GameError        `json:",omitempty"`
+	Welcome          *Welcome          `json:",omitempty"`
+	AboutToStart     *AboutToStart     `json:",omitempty"`
+	PresentQuestion  *PresentQuestion  `json:",omitempty"`
+	PlayerResult     *PlayerResult     `json:",omitempty"`
+	RoundSummary     *RoundSummary     `json:",omitempty"`
+	Summary          *Summary          `json:",omitempty"`
+	WordsUploaded    *WordsUploaded    `json:",omitempty"`
+	PowerUpsChanged  *PowerUpsChanged  `json:",omitempty"`
+	Eliminated       *Eliminated       `json:",omitempty"`
+	SuddenDeath      *SuddenDeath      `json:",omitempty"`
+	Bracket          *Bracket          `json:",omitempty"`
+	DailyLeaderboard *DailyLeaderboard `json:",omitempty"`
+	Error            *GameError        `json:",omitempty"`
 }
 
 // Welcome is sent to a player as they are waiting for the game to start
 type Welcome struct {
 	SecondsTillStart int
 	TargetScore      int
+	Language         string
+	QuestionModes    []QuestionMode
+	// Explains how points are awarded
+	Scoring string
+	// The teams to race in, and the team the player is in
+	Teams []string `json:",omitempty"`
+	Team  string   `json:",omitempty"`
+	// Sprints end after this many rounds or seconds instead of at the target score
+	RoundLimit       int `json:",omitempty"`
+	TimeLimitSeconds int `json:",omitempty"`
 }
 
 // AboutToStart tells all players that the game will start in X seconds
@@ -24,26 +40,138 @@ type AboutToStart struct {
 
 // PresentQuestion is the question sent to each player
 type PresentQuestion struct {
-	WordToGuess    string
+	Mode        QuestionMode
+	WordToGuess string
+	WordType    string
+	// The definition to type the word for in type-in rounds
+	Definition string `json:",omitempty"`
+	// The sentence to fill in the blank of in fill in the blank rounds
+	Sentence string `json:",omitempty"`
+	// The options to choose from. These are translations in translation rounds.
 	Definitions    []string
 	SecondsAllowed int
+	// True if the first correct answer wins the game
+	SuddenDeath bool `json:",omitempty"`
 }
 
 // PlayerResult is sent to the player telling them their result of the round
 type PlayerResult struct {
 	Correct       bool // todo: drop this field
 	CorrectAnswer int
+	// The word that had to be typed in type-in rounds
+	CorrectWord string `json:",omitempty"`
+	// True if a typed answer was close enough for partial points
+	NearMiss bool `json:",omitempty"`
+	// The power-up earned by the player's streak, if any
+	PowerUpEarned PowerUp `json:",omitempty"`
 }
 
 // RoundSummary is sent to each active player at the end of each round
 type RoundSummary struct {
 	PlayerStates []PlayerState
+	// The score of each team in team races
+	TeamStates []TeamState `json:",omitempty"`
 }
 
 // Summary is sent to the client at the end telling the player the final result
 type Summary struct {
 	Winner string
 	Icon   string
+	// True if the winner is a team
+	TeamWon bool `json:",omitempty"`
+	// True if more than one player shares first place. They are all at the top of the rankings.
+	Tie bool `json:",omitempty"`
+	// Every player from first to last
+	Rankings []PlayerRanking
+	// The words asked in a practice game
+	Review []ReviewedWord `json:",omitempty"`
+}
+
+// ReviewedWord is a word asked in a practice game, and whether the player got it right
+type ReviewedWord struct {
+	Word       string
+	Definition string
+	URL        string `json:",omitempty"`
+	Correct    bool
+}
+
+// SuddenDeath tells all players the players tied for first will race in sudden-death rounds
+// until one of them is first to answer correctly
+type SuddenDeath struct {
+	Players []PlayerState
+}
+
+// PlayerRanking is where a player finished in the game
+type PlayerRanking struct {
+	Position int
+	PlayerState
+	// The round the player was knocked out of an elimination race
+	EliminatedInRound int `json:",omitempty"`
+	// How long the player took to respond over the whole game, and on average
+	TotalResponseMillis   int64
+	AverageResponseMillis int64
+	// The fraction of the player's responses that were correct
+	Accuracy float64
+	// True if the player shares their position with another player
+	Tied bool `json:",omitempty"`
+}
+
+// Eliminated tells all players someone has been knocked out of an elimination race
+type Eliminated struct {
+	Player      PlayerState
+	Round       int
+	PlayersLeft int
+}
+
+// WordsUploaded tells the game creator their word list will be used for the game
+type WordsUploaded struct {
+	WordCount int
+}
+
+// PowerUpsChanged tells a player which power-ups they hold, and which will take effect in the
+// next round
+type PowerUpsChanged struct {
+	PowerUps       []PowerUp
+	QueuedPowerUps []PowerUp
+}
+
+// Bracket tells tournament players and spectators how the tournament is going
+type Bracket struct {
+	TournamentId string
+	Name         string
+	State        TournamentState
+	Entrants     int
+	// The heats of each round played so far
+	Rounds [][]BracketHeat
+	Winner string `json:",omitempty"`
+}
+
+// BracketHeat is one game in a round of a tournament
+type BracketHeat struct {
+	Players  []string
+	Advanced []string
+	Finished bool
+}
+
+// DailyLeaderboard ranks everyone who has played the daily challenge
+type DailyLeaderboard struct {
+	Date     string
+	Rankings []DailyRanking
+}
+
+// DailyRanking is a player's place on the daily leaderboard
+type DailyRanking struct {
+	Position            int
+	Name                string
+	Icon                string
+	Points              int
+	CorrectAnswers      int
+	TotalResponseMillis int64
+}
+
+// GameError tells the player their request could not be processed
+type GameError struct {
+	Message string
 }
 
 // PlayerState is a summary of player info as part of the round summary
@@ -53,4 +181,17 @@ type PlayerState struct {
 	Icon   string
 	Score  int
 	Active bool
+	// Number of correct answers in a row
+	Streak int
+	// The player's team in team races
+	Team string `json:",omitempty"`
+	// True if the player has been knocked out of an elimination race
+	Eliminated bool `json:",omitempty"`
+}
+
+// TeamState is a summary of a team's progress as part of the round summary
+type TeamState struct {
+	Name    string
+	Score   int
+	Members int
 }
@@ -1,6 +1,9 @@
 package model
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 // Player represents a player that started playing a game. The JSON metadata is for converting
 // this struct into a DynamoDB item.
@@ -17,10 +20,45 @@ type Player struct {
 	Responded bool `json:"responded"`
 	// Name of this player
 	Name string `json:"name"`
+	// The id the player's client keeps between connections, if it sent one
+	PlayerId string `json:"player_id,omitempty"`
 	// Client-specific icon to represent the player
 	Icon string `json:"icon"`
 	// Points for this player
 	Points int `json:"points"`
+	// Number of correct answers in a row
+	Streak int `json:"streak"`
+	// Power-ups the player holds, has used for the next round, and has in effect this round
+	PowerUps       []PowerUp `json:"power_ups"`
+	QueuedPowerUps []PowerUp `json:"queued_power_ups"`
+	RoundPowerUps  []PowerUp `json:"round_power_ups"`
+	// Where each option the player was shown sits in the question. Empty when the player was
+	// shown every option in order.
+	OptionIndexes []int `json:"option_indexes"`
+	// The team the player races in, if the game has teams
+	Team string `json:"team"`
+	// When the current round's question was sent to the player
+	QuestionSentAt time.Time `json:"question_sent_at"`
+	// Points for the current round, and how long the player took to respond
+	RoundPoints    int   `json:"round_points"`
+	ResponseMillis int64 `json:"response_millis"`
+	// How close the player's response was this round, and the power-up it earned them, if any
+	RoundAccuracy float64 `json:"round_accuracy"`
+	PowerUpEarned PowerUp `json:"power_up_earned"`
+	// How long the player has taken to respond over the whole game
+	TotalResponseMillis int64 `json:"total_response_millis"`
+	// How many questions the player has answered, and how many of those were correct
+	RoundsAnswered int `json:"rounds_answered"`
+	CorrectAnswers int `json:"correct_answers"`
+	// How long the player took to give each correct answer, for spotting scripts
+	CorrectResponseMillis []int64 `json:"correct_response_millis"`
+	// Whether the player has been knocked out of an elimination race, and in which round
+	Eliminated        bool `json:"eliminated"`
+	EliminatedInRound int  `json:"eliminated_in_round"`
+	// Whether the player is watching a sudden-death round they aren't part of, and whether they
+	// won sudden death
+	Spectating     bool `json:"spectating"`
+	WonSuddenDeath bool `json:"won_sudden_death"`
 	// Time this record will expire
 	ExpiresAt int64 `json:"expires_at"`
 }
@@ -28,10 +66,13 @@ type Player struct {
 func (p Player) PlayerState() PlayerState {
 	cleanId := strings.ReplaceAll(p.ConnectionId, "=", "")
 	return PlayerState{
-		Id:     cleanId,
-		Name:   p.Name,
-		Score:  p.Points,
-		Active: p.Active,
-		Icon:   p.Icon,
+		Id:         cleanId,
+		Name:       p.Name,
+		Score:      p.Points,
+		Active:     p.Active,
+		Icon:       p.Icon,
+		Streak:     p.Streak,
+		Team:       p.Team,
+		Eliminated: p.Eliminated,
 	}
 }
@@ -34,13 +34,15 @@ func (players Players) NumActivePlayers() int {
 }
 
 // PlayerWithHighestPoints returns the player with the maximum points. They may not have actually won yet.
+// Returns nil if there are no players.
 func (players Players) PlayerWithHighestPoints() *Player {
-	maxScore := -1
-	var winner *Player
+	if len(players) == 0 {
+		return nil
+	}
+	winner := players[0]
 
-	for _, p := range players {
-		if p.Points > maxScore {
-			maxScore = p.Points
+	for _, p := range players[1:] {
+		if p.Points > winner.Points {
 			winner = p
 		}
 	}
@@ -48,15 +50,25 @@ func (players Players) PlayerWithHighestPoints() *Player {
 	return winner
 }
 
+// AllActivePlayersResponded returns true if every player still racing has responded
 func (players Players) AllActivePlayersResponded() bool {
 	for _, player := range players {
-		if player.Active && !player.Responded {
+		if player.IsRacing() && !player.Responded {
 			return false
 		}
 	}
 	return true
 }
 
+// ResetStreaksOfNonResponders ends the streak of active players who missed the last question
+func (players Players) ResetStreaksOfNonResponders() {
+	for _, p := range players {
+		if p.IsRacing() && !p.Responded {
+			p.Streak = 0
+		}
+	}
+}
+
 func (players Players) SetActivesToNotResponded() {
 	for _, p := range players {
 		if p.Active {
@@ -0,0 +1,171 @@
+package model
+
+import "math/rand"
+
+// PowerUp is something a player can earn and spend to get an edge in a round
+type PowerUp string
+
+const (
+	// FiftyFifty removes one wrong option from the player's next question
+	FiftyFifty = PowerUp("FIFTY_FIFTY")
+	// DoublePoints doubles the player's points in the next round
+	DoublePoints = PowerUp("DOUBLE_POINTS")
+	// TimeFreeze gives the player extra time to answer the next question
+	TimeFreeze = PowerUp("TIME_FREEZE")
+)
+
+// PowerUps are all the power-ups that can be earned
+var PowerUps = []PowerUp{FiftyFifty, DoublePoints, TimeFreeze}
+
+// PowerUpStreak is how many correct answers in a row earn a power-up
+const PowerUpStreak = 3
+
+// MaxPowerUps is the most power-ups a player can hold at once
+const MaxPowerUps = 3
+
+// TimeFreezeSeconds is how much extra time a time freeze gives
+const TimeFreezeSeconds = 5
+
+// IsValid returns true if the power-up is known
+func (powerUp PowerUp) IsValid() bool {
+	for _, known := range PowerUps {
+		if powerUp == known {
+			return true
+		}
+	}
+	return false
+}
+
+// EarnPowerUp gives the player a random power-up if their streak has earned one and they have
+// room for it, picked with rng. Returns the power-up earned, or "" if none was.
+func (p *Player) EarnPowerUp(rng *rand.Rand) PowerUp {
+	if p.Streak == 0 || p.Streak%PowerUpStreak != 0 || len(p.PowerUps) >= MaxPowerUps {
+		return ""
+	}
+	powerUp := PowerUps[rng.Intn(len(PowerUps))]
+	p.PowerUps = append(p.PowerUps, powerUp)
+	return powerUp
+}
+
+// HeldPowerUp returns where the power-up is in the player's inventory, or -1 if they don't have it
+func (p Player) HeldPowerUp(powerUp PowerUp) int {
+	for i, held := range p.PowerUps {
+		if held == powerUp {
+			return i
+		}
+	}
+	return -1
+}
+
+// UsePowerUp takes the power-up out of the player's inventory, ready to take effect in the next
+// round. Returns false if the player doesn't have it.
+func (p *Player) UsePowerUp(powerUp PowerUp) bool {
+	i := p.HeldPowerUp(powerUp)
+	if i < 0 {
+		return false
+	}
+	p.PowerUps = append(p.PowerUps[:i:i], p.PowerUps[i+1:]...)
+	p.QueuedPowerUps = append(p.QueuedPowerUps[:len(p.QueuedPowerUps):len(p.QueuedPowerUps)], powerUp)
+	return true
+}
+
+// StartRound clears the player's last round and puts their queued power-ups into effect for the
+// question. Returns the question as this player sees it, with the options shuffled if asked so
+// players can't share answers by position. A 50/50 stays queued until there are enough options
+// to remove one.
+func (p *Player) StartRound(rng *rand.Rand, question Question, shuffle bool) Question {
+	p.RoundPoints = 0
+	p.ResponseMillis = 0
+	p.RoundAccuracy = 0
+	p.PowerUpEarned = ""
+	p.RoundPowerUps = nil
+	p.OptionIndexes = nil
+
+	queued := p.QueuedPowerUps
+	p.QueuedPowerUps = nil
+	for _, powerUp := range queued {
+		if powerUp == FiftyFifty && (!question.Mode.IsMultipleChoice() || len(question.Options) < 3) {
+			p.QueuedPowerUps = append(p.QueuedPowerUps, powerUp)
+			continue
+		}
+		p.RoundPowerUps = append(p.RoundPowerUps, powerUp)
+	}
+
+	fiftyFifty := p.HasRoundPowerUp(FiftyFifty)
+	shuffle = shuffle && question.Mode.IsMultipleChoice() && len(question.Options) > 1
+	if !fiftyFifty && !shuffle {
+		return question
+	}
+
+	// Pick a wrong option to remove
+	removed := -1
+	if fiftyFifty {
+		removed = rng.Intn(len(question.Options) - 1)
+		if removed >= question.CorrectAnswer {
+			removed++
+		}
+	}
+
+	// Remember where each option the player is shown came from
+	p.OptionIndexes = make([]int, 0, len(question.Options))
+	for i := range question.Options {
+		if i != removed {
+			p.OptionIndexes = append(p.OptionIndexes, i)
+		}
+	}
+	if shuffle {
+		rng.Shuffle(len(p.OptionIndexes), func(i, j int) {
+			p.OptionIndexes[i], p.OptionIndexes[j] = p.OptionIndexes[j], p.OptionIndexes[i]
+		})
+	}
+
+	personal := question
+	personal.Options = make([]string, len(p.OptionIndexes))
+	for shown, original := range p.OptionIndexes {
+		personal.Options[shown] = question.Options[original]
+	}
+	personal.CorrectAnswer = p.ShownOption(question.CorrectAnswer)
+	return personal
+}
+
+// HasRoundPowerUp returns true if the power-up is in effect for this round
+func (p Player) HasRoundPowerUp(powerUp PowerUp) bool {
+	for _, active := range p.RoundPowerUps {
+		if active == powerUp {
+			return true
+		}
+	}
+	return false
+}
+
+// SecondsAllowed returns how long the player has to answer the current question
+func (p Player) SecondsAllowed(secondsPerQuestion int) int {
+	if p.HasRoundPowerUp(TimeFreeze) {
+		return secondsPerQuestion + TimeFreezeSeconds
+	}
+	return secondsPerQuestion
+}
+
+// OriginalOption converts an option the player chose into its position in the question
+func (p Player) OriginalOption(option int) int {
+	if len(p.OptionIndexes) == 0 {
+		return option
+	}
+	if option < 0 || option >= len(p.OptionIndexes) {
+		return -1
+	}
+	return p.OptionIndexes[option]
+}
+
+// ShownOption converts a position in the question into the option the player was shown
+func (p Player) ShownOption(option int) int {
+	if len(p.OptionIndexes) == 0 {
+		return option
+	}
+	for shown, original := range p.OptionIndexes {
+		if original == option {
+			return shown
+		}
+	}
+	return -1
+}
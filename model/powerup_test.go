@@ -0,0 +1,128 @@
+package model
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestFiftyFifty(t *testing.T) {
+	question := Question{Mode: DefinitionMode, Options: []string{"a", "b", "c", "d"}, CorrectAnswer: 2}
+	player := Player{PowerUps: []PowerUp{FiftyFifty}}
+
+	if !player.UsePowerUp(FiftyFifty) {
+		t.Fatal("Expected the player to be able to use their power-up")
+	}
+	if player.UsePowerUp(FiftyFifty) {
+		t.Error("Expected the power-up to be used up")
+	}
+
+	personal := player.StartRound(rand.New(rand.NewSource(1)), question, false)
+	if len(personal.Options) != 3 {
+		t.Fatalf("Got %d options and expected 3", len(personal.Options))
+	}
+	if personal.Options[personal.CorrectAnswer] != "c" {
+		t.Errorf("Correct option moved to %q", personal.Options[personal.CorrectAnswer])
+	}
+	if player.OriginalOption(personal.CorrectAnswer) != question.CorrectAnswer {
+		t.Errorf("Got original option %d and expected %d", player.OriginalOption(personal.CorrectAnswer), question.CorrectAnswer)
+	}
+	if player.ShownOption(question.CorrectAnswer) != personal.CorrectAnswer {
+		t.Errorf("Got shown option %d and expected %d", player.ShownOption(question.CorrectAnswer), personal.CorrectAnswer)
+	}
+
+	// The 50/50 only lasts one round
+	if next := player.StartRound(rand.New(rand.NewSource(1)), question, false); len(next.Options) != 4 {
+		t.Errorf("Got %d options in the next round and expected 4", len(next.Options))
+	}
+}
+
+func TestShuffleOptions(t *testing.T) {
+	question := Question{Mode: DefinitionMode, Options: []string{"a", "b", "c", "d", "e", "f"}, CorrectAnswer: 2}
+	player := Player{QueuedPowerUps: []PowerUp{FiftyFifty}}
+
+	personal := player.StartRound(rand.New(rand.NewSource(1)), question, true)
+	if len(personal.Options) != 5 {
+		t.Fatalf("Got %d options and expected 5", len(personal.Options))
+	}
+	if personal.Options[personal.CorrectAnswer] != "c" {
+		t.Errorf("Got correct option %q and expected %q", personal.Options[personal.CorrectAnswer], "c")
+	}
+	inOrder := true
+	for shown, option := range personal.Options {
+		if player.OriginalOption(shown) < 0 || question.Options[player.OriginalOption(shown)] != option {
+			t.Errorf("Got option %q shown at %d mapped back to %d", option, shown, player.OriginalOption(shown))
+		}
+		if shown > 0 && player.OriginalOption(shown) < player.OriginalOption(shown-1) {
+			inOrder = false
+		}
+	}
+	if inOrder {
+		t.Errorf("Got options %v in their original order", personal.Options)
+	}
+}
+
+func TestGame_PlayerResult(t *testing.T) {
+	question := Question{Mode: DefinitionMode, Options: []string{"a", "b", "c", "d"}, CorrectAnswer: 1}
+	game := Game{CorrectAnswer: question.CorrectAnswer}
+	player := Player{}
+	personal := player.StartRound(rand.New(rand.NewSource(1)), question, true)
+
+	player.RoundAccuracy = 1
+	player.PowerUpEarned = TimeFreeze
+	got := game.PlayerResult(player)
+	if !got.Correct || got.CorrectAnswer != personal.CorrectAnswer || got.PowerUpEarned != TimeFreeze {
+		t.Errorf("Got result %+v and expected the correct answer at %d", got, personal.CorrectAnswer)
+	}
+
+	// A new round forgets the last result
+	player.StartRound(rand.New(rand.NewSource(1)), question, true)
+	if got := game.PlayerResult(player); got.Correct || got.PowerUpEarned != "" {
+		t.Errorf("Got result %+v carried over from the last round", got)
+	}
+}
+
+func TestFiftyFiftyWaitsForMultipleChoice(t *testing.T) {
+	player := Player{QueuedPowerUps: []PowerUp{FiftyFifty, TimeFreeze}}
+
+	player.StartRound(rand.New(rand.NewSource(1)), Question{Mode: TypeInMode, CorrectAnswer: -1}, true)
+	if !player.HasRoundPowerUp(TimeFreeze) || player.HasRoundPowerUp(FiftyFifty) {
+		t.Errorf("Got round power-ups %v", player.RoundPowerUps)
+	}
+	if len(player.QueuedPowerUps) != 1 || player.QueuedPowerUps[0] != FiftyFifty {
+		t.Errorf("Got queued power-ups %v", player.QueuedPowerUps)
+	}
+	if player.SecondsAllowed(10) != 10+TimeFreezeSeconds {
+		t.Errorf("Got %d seconds allowed", player.SecondsAllowed(10))
+	}
+}
+
+func TestEarnPowerUp(t *testing.T) {
+	player := Player{Streak: PowerUpStreak - 1}
+	if player.EarnPowerUp(rand.New(rand.NewSource(1))) != "" {
+		t.Error("Expected no power-up before the streak is long enough")
+	}
+	player.Streak = PowerUpStreak
+	earned := player.EarnPowerUp(rand.New(rand.NewSource(1)))
+	if !earned.IsValid() || len(player.PowerUps) != 1 {
+		t.Errorf("Expected a power-up but got %v", player.PowerUps)
+	}
+
+	// The same source of randomness earns the same power-up
+	other := Player{Streak: PowerUpStreak}
+	if again := other.EarnPowerUp(rand.New(rand.NewSource(1))); again != earned {
+		t.Errorf("Got %s and expected %s", again, earned)
+	}
+}
+
+func TestUsePowerUpLeavesCopiesAlone(t *testing.T) {
+	read := Player{PowerUps: []PowerUp{DoublePoints, FiftyFifty}, QueuedPowerUps: make([]PowerUp, 0, 2)}
+	if index := read.HeldPowerUp(FiftyFifty); index != 1 {
+		t.Fatalf("Got index %d and expected 1", index)
+	}
+
+	used := read
+	used.UsePowerUp(FiftyFifty)
+	if read.PowerUps[1] != FiftyFifty || read.QueuedPowerUps[:1][0] != "" || len(used.QueuedPowerUps) != 1 {
+		t.Errorf("Got power-ups %v and %v after using one from a copy", read.PowerUps, read.QueuedPowerUps)
+	}
+}
@@ -0,0 +1,59 @@
+package model
+
+// PracticeGameRounds is how many questions are asked in a practice game
+const PracticeGameRounds = 10
+
+// PracticeRound is a word asked in a practice game. The JSON metadata is for converting this
+// struct into a DynamoDB item.
+type PracticeRound struct {
+	Word       string `json:"word"`
+	Definition string `json:"definition"`
+	URL        string `json:"url"`
+	Correct    bool   `json:"correct"`
+}
+
+// IsPractice returns true if the game is a player practicing on their own
+func (game *Game) IsPractice() bool {
+	return game.Practice
+}
+
+// AddPracticeRound remembers the word asked in the round so it can be reviewed at the end
+func (game *Game) AddPracticeRound(question Question) {
+	game.PracticeRounds = append(game.PracticeRounds, PracticeRound{
+		Word:       question.Word.Word,
+		Definition: question.Word.Definition,
+		URL:        question.Word.URL,
+	})
+}
+
+// AskedInPractice returns true if the word has already been asked in this practice game
+func (game *Game) AskedInPractice(word string) bool {
+	for _, round := range game.PracticeRounds {
+		if round.Word == word {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordPracticeAnswer records whether the player got the latest word right
+func (game *Game) RecordPracticeAnswer(correct bool) {
+	if len(game.PracticeRounds) == 0 {
+		return
+	}
+	game.PracticeRounds[len(game.PracticeRounds)-1].Correct = correct
+}
+
+// PracticeReview lists the words asked in a practice game, in the order they were asked
+func (game *Game) PracticeReview() []ReviewedWord {
+	review := make([]ReviewedWord, len(game.PracticeRounds))
+	for i, round := range game.PracticeRounds {
+		review[i] = ReviewedWord{
+			Word:       round.Word,
+			Definition: round.Definition,
+			URL:        round.URL,
+			Correct:    round.Correct,
+		}
+	}
+	return review
+}
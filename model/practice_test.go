@@ -0,0 +1,25 @@
+package model
+
+import "testing"
+
+func TestGame_PracticeReview(t *testing.T) {
+	game := Game{Practice: true}
+	game.AddPracticeRound(NewTypeInQuestion(Word{Word: "terse", WordType: "adjective", Definition: "brief", URL: "https://example.com/terse"}, DefaultLanguage))
+	game.RecordPracticeAnswer(true)
+	game.AddPracticeRound(NewTypeInQuestion(Word{Word: "verbose", WordType: "adjective", Definition: "wordy"}, DefaultLanguage))
+	game.RecordPracticeAnswer(false)
+
+	got := game.PracticeReview()
+	expected := []ReviewedWord{
+		{Word: "terse", Definition: "brief", URL: "https://example.com/terse", Correct: true},
+		{Word: "verbose", Definition: "wordy", Correct: false},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Got %d reviewed words and expected %d", len(got), len(expected))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Got %v and expected %v", got[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package model
+
+import "math/rand"
+
+// QuestionMode is the kind of question asked in a round
+type QuestionMode string
+
+const (
+	// DefinitionMode asks players to pick the definition of a word
+	DefinitionMode = QuestionMode("DEFINITION")
+	// TranslationMode asks players to pick the translation of a word
+	TranslationMode = QuestionMode("TRANSLATION")
+	// TypeInMode asks players to type the word that matches a definition
+	TypeInMode = QuestionMode("TYPE_IN")
+	// PartOfSpeechMode asks players to pick the part of speech of a word
+	PartOfSpeechMode = QuestionMode("PART_OF_SPEECH")
+	// FillInBlankMode asks players to pick the word missing from an example sentence
+	FillInBlankMode = QuestionMode("FILL_IN_BLANK")
+	// SynonymMode asks players to pick the word that means the same as a word
+	SynonymMode = QuestionMode("SYNONYM")
+	// AntonymMode asks players to pick the word that means the opposite of a word
+	AntonymMode = QuestionMode("ANTONYM")
+)
+
+// IsValid returns true if the question mode is known
+func (mode QuestionMode) IsValid() bool {
+	switch mode {
+	case DefinitionMode, TranslationMode, TypeInMode, PartOfSpeechMode, FillInBlankMode, SynonymMode, AntonymMode:
+		return true
+	}
+	return false
+}
+
+// Question is what players are asked in a round
+type Question struct {
+	Mode        QuestionMode
+	WordToGuess string
+	// The localized type of the word to guess
+	WordType string
+	// The definition shown when players type in the word, or as a hint for the part of speech
+	Definition string
+	// The example sentence with the word blanked out in fill in the blank rounds
+	Sentence string
+	// The options players choose from. There are none when players type in the word.
+	Options       []string
+	CorrectAnswer int
+	// The word players have to type in
+	CorrectWord string
+	// The word the question is about, for reviewing afterwards
+	Word Word
+}
+
+// IsMultipleChoice returns true if players choose from options rather than typing an answer
+func (mode QuestionMode) IsMultipleChoice() bool {
+	return mode != TypeInMode
+}
+
+// NewDefinitionQuestion asks for the definition of the word at the correct answer index
+func NewDefinitionQuestion(words Words, correctAnswer int, language string) Question {
+	return Question{
+		Mode:          DefinitionMode,
+		WordToGuess:   words[correctAnswer].Word,
+		WordType:      LocalizedWordType(language, words[correctAnswer].WordType),
+		Options:       words.GetDefinitions(),
+		CorrectAnswer: correctAnswer,
+		Word:          words[correctAnswer],
+	}
+}
+
+// NewTranslationQuestion asks for the translation of the word at the correct answer index
+func NewTranslationQuestion(words Words, correctAnswer int, language string, translateTo string) Question {
+	options := make([]string, len(words))
+	for i, word := range words {
+		options[i] = word.Translations[translateTo]
+	}
+
+	return Question{
+		Mode:          TranslationMode,
+		WordToGuess:   words[correctAnswer].Word,
+		WordType:      LocalizedWordType(language, words[correctAnswer].WordType),
+		Options:       options,
+		CorrectAnswer: correctAnswer,
+		Word:          words[correctAnswer],
+	}
+}
+
+// NewTypeInQuestion shows the definition of a word, which players have to type in
+func NewTypeInQuestion(word Word, language string) Question {
+	return Question{
+		Mode:          TypeInMode,
+		WordType:      LocalizedWordType(language, word.WordType),
+		Definition:    word.Definition,
+		CorrectAnswer: -1,
+		CorrectWord:   word.Word,
+		Word:          word,
+	}
+}
+
+// NewPartOfSpeechQuestion asks for the part of speech of a word, optionally giving its definition
+// as a hint. The options are localized names of the main parts of speech.
+func NewPartOfSpeechQuestion(rng *rand.Rand, word Word, numberOfOptions int, language string, showDefinition bool) Question {
+	// Pick the other parts of speech to offer
+	otherTypes := make([]string, 0, len(WordTypes))
+	for _, wordType := range WordTypes {
+		if wordType != word.WordType {
+			otherTypes = append(otherTypes, wordType)
+		}
+	}
+	rng.Shuffle(len(otherTypes), func(i, j int) {
+		otherTypes[i], otherTypes[j] = otherTypes[j], otherTypes[i]
+	})
+	if numberOfOptions-1 < len(otherTypes) {
+		otherTypes = otherTypes[:numberOfOptions-1]
+	}
+
+	wrongOptions := make([]string, len(otherTypes))
+	for i, wordType := range otherTypes {
+		wrongOptions[i] = LocalizedWordType(language, wordType)
+	}
+	options, correctAnswer := addCorrectOption(rng, wrongOptions, LocalizedWordType(language, word.WordType))
+
+	question := Question{
+		Mode:          PartOfSpeechMode,
+		WordToGuess:   word.Word,
+		Options:       options,
+		CorrectAnswer: correctAnswer,
+		Word:          word,
+	}
+	if showDefinition {
+		question.Definition = word.Definition
+	}
+	return question
+}
+
+// NewFillInBlankQuestion shows one of the word's example sentences with the word blanked out,
+// and offers the word amongst the distractors. Returns false if none of the word's examples
+// contain the word.
+func NewFillInBlankQuestion(rng *rand.Rand, word Word, distractors Words, language string) (Question, bool) {
+	blankedSentences := make([]string, 0, len(word.Examples))
+	for _, example := range word.Examples {
+		if blankedSentence, blanked := BlankOutWord(example, word.Word); blanked {
+			blankedSentences = append(blankedSentences, blankedSentence)
+		}
+	}
+	if len(blankedSentences) == 0 {
+		return Question{}, false
+	}
+
+	wrongOptions := make([]string, len(distractors))
+	for i, distractor := range distractors {
+		wrongOptions[i] = distractor.Word
+	}
+	options, correctAnswer := addCorrectOption(rng, wrongOptions, word.Word)
+
+	return Question{
+		Mode:          FillInBlankMode,
+		WordType:      LocalizedWordType(language, word.WordType),
+		Sentence:      blankedSentences[rng.Intn(len(blankedSentences))],
+		Options:       options,
+		CorrectAnswer: correctAnswer,
+		Word:          word,
+	}, true
+}
+
+// NewRelatedWordQuestion asks which option means the same as the word in synonym rounds, or the
+// opposite of the word in antonym rounds. The distractors must not be related to the word.
+func NewRelatedWordQuestion(rng *rand.Rand, mode QuestionMode, word Word, distractors Words, language string) Question {
+	relatedWords := word.Synonyms
+	if mode == AntonymMode {
+		relatedWords = word.Antonyms
+	}
+
+	wrongOptions := make([]string, len(distractors))
+	for i, distractor := range distractors {
+		wrongOptions[i] = distractor.Word
+	}
+	options, correctAnswer := addCorrectOption(rng, wrongOptions, relatedWords[rng.Intn(len(relatedWords))])
+
+	return Question{
+		Mode:          mode,
+		WordToGuess:   word.Word,
+		WordType:      LocalizedWordType(language, word.WordType),
+		Options:       options,
+		CorrectAnswer: correctAnswer,
+		Word:          word,
+	}
+}
+
+// addCorrectOption puts the correct option amongst the wrong ones at a random position, and
+// returns the options with the position of the correct one
+func addCorrectOption(rng *rand.Rand, wrongOptions []string, correctOption string) ([]string, int) {
+	options := make([]string, len(wrongOptions)+1)
+	correctAnswer := rng.Intn(len(options))
+	for i := range options {
+		switch {
+		case i < correctAnswer:
+			options[i] = wrongOptions[i]
+		case i == correctAnswer:
+			options[i] = correctOption
+		default:
+			options[i] = wrongOptions[i-1]
+		}
+	}
+	return options, correctAnswer
+}
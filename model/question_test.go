@@ -0,0 +1,29 @@
+package model
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNewPartOfSpeechQuestion(t *testing.T) {
+	word := Word{Word: "rápido", WordType: "adjective", Definition: "que se mueve deprisa"}
+
+	got := NewPartOfSpeechQuestion(rand.New(rand.NewSource(1)), word, 3, "es", false)
+	if len(got.Options) != 3 {
+		t.Fatalf("Got %d options and expected %d", len(got.Options), 3)
+	}
+	if got.Options[got.CorrectAnswer] != "adjetivo" {
+		t.Errorf("Got correct option %s and expected %s", got.Options[got.CorrectAnswer], "adjetivo")
+	}
+	if got.Definition != "" {
+		t.Errorf("Got definition %s and expected none", got.Definition)
+	}
+
+	seen := make(map[string]bool)
+	for _, option := range got.Options {
+		if seen[option] {
+			t.Errorf("Got option %s more than once", option)
+		}
+		seen[option] = true
+	}
+}
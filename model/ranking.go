@@ -0,0 +1,112 @@
+package model
+
+import "sort"
+
+// TieBreaker decides the order of players who finish with the same score
+type TieBreaker string
+
+const (
+	// NoTieBreaker lets players with the same score share a position
+	NoTieBreaker = TieBreaker("NONE")
+	// FastestTieBreaker puts the player who took the least time to respond over the game first
+	FastestTieBreaker = TieBreaker("FASTEST")
+	// SuddenDeathTieBreaker has the players tied for first race on until one of them is the
+	// first to answer a question correctly
+	SuddenDeathTieBreaker = TieBreaker("SUDDEN_DEATH")
+)
+
+// IsValid returns true if the tie-breaker is known
+func (tieBreaker TieBreaker) IsValid() bool {
+	switch tieBreaker {
+	case NoTieBreaker, FastestTieBreaker, SuddenDeathTieBreaker:
+		return true
+	}
+	return false
+}
+
+// Accuracy returns the fraction of the player's responses that were correct
+func (p Player) Accuracy() float64 {
+	if p.RoundsAnswered == 0 {
+		return 0
+	}
+	return float64(p.CorrectAnswers) / float64(p.RoundsAnswered)
+}
+
+// AverageResponseMillis returns how long the player took to respond on average
+func (p Player) AverageResponseMillis() int64 {
+	if p.RoundsAnswered == 0 {
+		return 0
+	}
+	return p.TotalResponseMillis / int64(p.RoundsAnswered)
+}
+
+// Rankings orders players from first to last. Players still in the race come first by score,
+// followed by eliminated players, the last to be eliminated first. Players the tie-breaker
+// can't separate share a position and are marked as tied.
+func (players Players) Rankings(tieBreaker TieBreaker) []PlayerRanking {
+	ranked := players.inFinishingOrder(tieBreaker)
+
+	rankings := make([]PlayerRanking, len(ranked))
+	for i, p := range ranked {
+		rankings[i] = PlayerRanking{
+			Position:              i + 1,
+			PlayerState:           p.PlayerState(),
+			EliminatedInRound:     p.EliminatedInRound,
+			TotalResponseMillis:   p.TotalResponseMillis,
+			Accuracy:              p.Accuracy(),
+			AverageResponseMillis: p.AverageResponseMillis(),
+		}
+		if i > 0 && compareFinish(ranked[i-1], p, tieBreaker) == 0 {
+			rankings[i].Position = rankings[i-1].Position
+			rankings[i].Tied = true
+			rankings[i-1].Tied = true
+		}
+	}
+	return rankings
+}
+
+// FinishingOrder returns the connection ids of the players still connected, from first to last
+func (players Players) FinishingOrder(tieBreaker TieBreaker) []string {
+	ranked := players.inFinishingOrder(tieBreaker)
+	connectionIds := make([]string, 0, len(ranked))
+	for _, p := range ranked {
+		if p.Active {
+			connectionIds = append(connectionIds, p.ConnectionId)
+		}
+	}
+	return connectionIds
+}
+
+func (players Players) inFinishingOrder(tieBreaker TieBreaker) Players {
+	ranked := make(Players, len(players))
+	copy(ranked, players)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return compareFinish(ranked[i], ranked[j], tieBreaker) < 0
+	})
+	return ranked
+}
+
+// compareFinish returns a negative number if the first player finished ahead of the second, a
+// positive number if they finished behind, and zero if they are tied
+func compareFinish(p, other *Player, tieBreaker TieBreaker) int {
+	switch {
+	case p.Eliminated != other.Eliminated || p.EliminatedInRound != other.EliminatedInRound:
+		if eliminatedLater(p, other) {
+			return -1
+		}
+		return 1
+	case p.Points != other.Points:
+		return other.Points - p.Points
+	case p.WonSuddenDeath != other.WonSuddenDeath:
+		if p.WonSuddenDeath {
+			return -1
+		}
+		return 1
+	case tieBreaker == FastestTieBreaker && p.TotalResponseMillis != other.TotalResponseMillis:
+		if p.TotalResponseMillis < other.TotalResponseMillis {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
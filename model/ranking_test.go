@@ -0,0 +1,35 @@
+package model
+
+import "testing"
+
+func TestRankings(t *testing.T) {
+	players := Players{
+		{Name: "Ann", Points: 300, TotalResponseMillis: 9000, RoundsAnswered: 3, CorrectAnswers: 2},
+		{Name: "Bob", Points: 300, TotalResponseMillis: 6000, RoundsAnswered: 3, CorrectAnswers: 3},
+		{Name: "Cat", Points: 400, TotalResponseMillis: 20000, RoundsAnswered: 4, CorrectAnswers: 4},
+	}
+	tests := []struct {
+		tieBreaker        TieBreaker
+		expectedNames     []string
+		expectedPositions []int
+		expectedTied      []bool
+	}{
+		{FastestTieBreaker, []string{"Cat", "Bob", "Ann"}, []int{1, 2, 3}, []bool{false, false, false}},
+		{NoTieBreaker, []string{"Cat", "Ann", "Bob"}, []int{1, 2, 2}, []bool{false, true, true}},
+	}
+
+	for _, test := range tests {
+		rankings := players.Rankings(test.tieBreaker)
+		for i, ranking := range rankings {
+			if ranking.Name != test.expectedNames[i] || ranking.Position != test.expectedPositions[i] || ranking.Tied != test.expectedTied[i] {
+				t.Errorf("%s: got %s at %d (tied %v) and expected %s at %d (tied %v)", test.tieBreaker,
+					ranking.Name, ranking.Position, ranking.Tied, test.expectedNames[i], test.expectedPositions[i], test.expectedTied[i])
+			}
+		}
+	}
+
+	bob := players.Rankings(FastestTieBreaker)[1]
+	if bob.Accuracy != 1 || bob.AverageResponseMillis != 2000 {
+		t.Errorf("Got accuracy %v and average time %d", bob.Accuracy, bob.AverageResponseMillis)
+	}
+}
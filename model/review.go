@@ -0,0 +1,116 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// The ease factor new cards start with, and the lowest it can drop to
+const (
+	initialEaseFactor = 2.5
+	minimumEaseFactor = 1.3
+)
+
+// ReviewDeck holds the words a player has got wrong, scheduled for review using the SM-2 spaced
+// repetition algorithm. The JSON metadata is for converting this struct into a DynamoDB item.
+type ReviewDeck struct {
+	PlayerId string       `json:"player_id"`
+	Cards    []ReviewCard `json:"cards"`
+}
+
+// ReviewCard is the review schedule of one word
+type ReviewCard struct {
+	Word     string `json:"word"`
+	Language string `json:"language"`
+	// How many times in a row the word has been recalled
+	Repetitions int `json:"repetitions"`
+	// How easily the word is recalled. The higher it is, the longer the gap between reviews.
+	EaseFactor   float64   `json:"ease_factor"`
+	IntervalDays int       `json:"interval_days"`
+	DueAt        time.Time `json:"due_at"`
+}
+
+// IsReview returns true if the game asks a player the words they have missed before
+func (game *Game) IsReview() bool {
+	return game.ReviewFor != ""
+}
+
+// ReviewQuality grades how well a word was recalled, from 0 (blackout) to 5 (perfect), given the
+// player's response to a question about it
+func ReviewQuality(response ScoredResponse) int {
+	switch {
+	case response.IsCorrect() && response.Elapsed <= response.Allowed/2:
+		return 5
+	case response.IsCorrect():
+		return 4
+	case response.Accuracy > 0 && !response.IsLate():
+		return 2
+	default:
+		return 1
+	}
+}
+
+// RecordAnswer schedules the word's next review given how well it was recalled. Words recalled
+// well that aren't in the deck yet are left out, so only words the player has got wrong are
+// reviewed. Returns true if the deck changed.
+func (deck *ReviewDeck) RecordAnswer(word string, language string, quality int, now time.Time) bool {
+	for i := range deck.Cards {
+		if deck.Cards[i].Word == word && deck.Cards[i].Language == language {
+			deck.Cards[i].Review(quality, now)
+			return true
+		}
+	}
+	if quality >= 3 {
+		return false
+	}
+
+	card := ReviewCard{
+		Word:       word,
+		Language:   language,
+		EaseFactor: initialEaseFactor,
+	}
+	card.Review(quality, now)
+	deck.Cards = append(deck.Cards, card)
+	return true
+}
+
+// Review updates the card's schedule using SM-2. Words that weren't recalled start again and
+// are due the next day, and recalled words are due after a longer gap each time.
+func (card *ReviewCard) Review(quality int, now time.Time) {
+	if quality < 3 {
+		card.Repetitions = 0
+		card.IntervalDays = 1
+	} else {
+		card.Repetitions++
+		switch card.Repetitions {
+		case 1:
+			card.IntervalDays = 1
+		case 2:
+			card.IntervalDays = 6
+		default:
+			card.IntervalDays = int(math.Round(float64(card.IntervalDays) * card.EaseFactor))
+		}
+	}
+
+	difference := float64(5 - quality)
+	card.EaseFactor += 0.1 - difference*(0.08+difference*0.02)
+	if card.EaseFactor < minimumEaseFactor {
+		card.EaseFactor = minimumEaseFactor
+	}
+	card.DueAt = now.AddDate(0, 0, card.IntervalDays)
+}
+
+// DueCards returns the cards in the language that are due for review, the most overdue first
+func (deck *ReviewDeck) DueCards(language string, now time.Time) []ReviewCard {
+	due := make([]ReviewCard, 0)
+	for _, card := range deck.Cards {
+		if card.Language == language && !card.DueAt.After(now) {
+			due = append(due, card)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].DueAt.Before(due[j].DueAt)
+	})
+	return due
+}
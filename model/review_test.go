@@ -0,0 +1,45 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReviewDeck_RecordAnswer(t *testing.T) {
+	now := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	deck := ReviewDeck{PlayerId: "player"}
+
+	if deck.RecordAnswer("terse", DefaultLanguage, 5, now) {
+		t.Errorf("Got a card for a word that was recalled")
+	}
+	if !deck.RecordAnswer("laconic", DefaultLanguage, 1, now) {
+		t.Fatalf("Got no card for a word that was missed")
+	}
+	if len(deck.DueCards(DefaultLanguage, now)) != 0 {
+		t.Errorf("Got a card due straight after it was missed")
+	}
+
+	// Each time the word is recalled, the gap before the next review grows
+	expectedIntervals := []int{1, 6, 12}
+	for _, expected := range expectedIntervals {
+		now = deck.Cards[0].DueAt
+		due := deck.DueCards(DefaultLanguage, now)
+		if len(due) != 1 || due[0].Word != "laconic" {
+			t.Fatalf("Got %d cards due and expected laconic", len(due))
+		}
+		deck.RecordAnswer("laconic", DefaultLanguage, 4, now)
+		if deck.Cards[0].IntervalDays != expected {
+			t.Errorf("Got interval %d and expected %d", deck.Cards[0].IntervalDays, expected)
+		}
+	}
+
+	// Missing it again starts over
+	deck.RecordAnswer("laconic", DefaultLanguage, 1, now)
+	if deck.Cards[0].Repetitions != 0 || deck.Cards[0].IntervalDays != 1 {
+		t.Errorf("Got %d repetitions every %d days and expected to start over",
+			deck.Cards[0].Repetitions, deck.Cards[0].IntervalDays)
+	}
+	if deck.Cards[0].EaseFactor < minimumEaseFactor {
+		t.Errorf("Got ease factor %f below the minimum", deck.Cards[0].EaseFactor)
+	}
+}
@@ -0,0 +1,179 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScoringPolicyName identifies how points are awarded in a game
+type ScoringPolicyName string
+
+const (
+	// ClassicScoring awards points for a correct answer plus points for answering quickly
+	ClassicScoring = ScoringPolicyName("CLASSIC")
+	// AccuracyScoring only awards points for a correct answer
+	AccuracyScoring = ScoringPolicyName("ACCURACY")
+	// NegativeScoring is classic scoring, but takes points away for a wrong answer
+	NegativeScoring = ScoringPolicyName("NEGATIVE")
+	// StreakScoring multiplies classic points by the number of correct answers in a row
+	StreakScoring = ScoringPolicyName("STREAK")
+	// FirstCorrectScoring is classic scoring, with a bonus for the first correct answer of a round
+	FirstCorrectScoring = ScoringPolicyName("FIRST_CORRECT")
+)
+
+const (
+	correctPoints       = 100
+	maxSpeedPoints      = 50
+	wrongPenalty        = 50
+	firstCorrectBonus   = 50
+	streakStep          = 0.25
+	maxStreakMultiplier = 2.0
+)
+
+// ScoringPolicy decides how many points a response is worth
+type ScoringPolicy interface {
+	// Points returns the points awarded for the response
+	Points(response ScoredResponse) int
+	// Description explains the policy to players
+	Description() string
+}
+
+// ScoredResponse is everything a scoring policy can base points on
+type ScoredResponse struct {
+	// How close the response was to the correct answer, from 0 (wrong) to 1 (right)
+	Accuracy float64
+	// How long the player took to respond, and how long they were allowed
+	Elapsed time.Duration
+	Allowed time.Duration
+	// How many correct answers in a row the player had before this response
+	Streak int
+	// True if this was the first correct answer of the round
+	FirstCorrect bool
+	// True if the player used a double points power-up for the round
+	DoublePoints bool
+	// True if the response looked like cheating, so answering quickly isn't rewarded
+	NoSpeedPoints bool
+}
+
+// IsLate returns true if the player took longer than allowed
+func (response ScoredResponse) IsLate() bool {
+	return response.Elapsed > response.Allowed
+}
+
+// IsCorrect returns true if the response was exactly right
+func (response ScoredResponse) IsCorrect() bool {
+	return response.Accuracy == 1 && !response.IsLate()
+}
+
+// accuracyPoints are the points for a correct answer, with partial points for a near miss
+func (response ScoredResponse) accuracyPoints() int {
+	return int(correctPoints * response.Accuracy)
+}
+
+// speedPoints are the points for answering quickly
+func (response ScoredResponse) speedPoints() int {
+	if response.Allowed <= 0 || response.NoSpeedPoints {
+		return 0
+	}
+	timePoints := int(maxSpeedPoints * (response.Allowed - response.Elapsed) / response.Allowed)
+	if timePoints < 0 {
+		timePoints = 0
+	}
+	return timePoints
+}
+
+// ScoringPolicyByName returns the scoring policy with the given name
+func ScoringPolicyByName(name ScoringPolicyName) (ScoringPolicy, error) {
+	switch name {
+	case ClassicScoring, "":
+		return classicPolicy{}, nil
+	case AccuracyScoring:
+		return accuracyPolicy{}, nil
+	case NegativeScoring:
+		return negativePolicy{}, nil
+	case StreakScoring:
+		return streakPolicy{}, nil
+	case FirstCorrectScoring:
+		return firstCorrectPolicy{}, nil
+	}
+	return nil, fmt.Errorf("unknown scoring policy: %s", name)
+}
+
+type classicPolicy struct{}
+
+func (classicPolicy) Points(response ScoredResponse) int {
+	// Player took longer than allowed time - no points!
+	if response.IsLate() {
+		return 0
+	}
+	return response.accuracyPoints() + response.speedPoints()
+}
+
+func (classicPolicy) Description() string {
+	return fmt.Sprintf("%d points for a correct answer, plus up to %d for answering quickly", correctPoints, maxSpeedPoints)
+}
+
+type accuracyPolicy struct{}
+
+func (accuracyPolicy) Points(response ScoredResponse) int {
+	if response.IsLate() {
+		return 0
+	}
+	return response.accuracyPoints()
+}
+
+func (accuracyPolicy) Description() string {
+	return fmt.Sprintf("%d points for a correct answer. Speed doesn't matter.", correctPoints)
+}
+
+type negativePolicy struct{}
+
+func (negativePolicy) Points(response ScoredResponse) int {
+	if response.IsLate() {
+		return 0
+	}
+	if response.Accuracy == 0 {
+		return -wrongPenalty
+	}
+	return response.accuracyPoints() + response.speedPoints()
+}
+
+func (negativePolicy) Description() string {
+	return fmt.Sprintf("%d points for a correct answer, plus up to %d for answering quickly. Lose %d for a wrong answer!",
+		correctPoints, maxSpeedPoints, wrongPenalty)
+}
+
+type streakPolicy struct{}
+
+func (streakPolicy) Points(response ScoredResponse) int {
+	points := classicPolicy{}.Points(response)
+	if !response.IsCorrect() {
+		return points
+	}
+
+	multiplier := 1 + streakStep*float64(response.Streak)
+	if multiplier > maxStreakMultiplier {
+		multiplier = maxStreakMultiplier
+	}
+	return int(float64(points) * multiplier)
+}
+
+func (streakPolicy) Description() string {
+	return fmt.Sprintf("%d points for a correct answer, plus up to %d for answering quickly. "+
+		"Each correct answer in a row multiplies your points, up to %.0fx!", correctPoints, maxSpeedPoints, maxStreakMultiplier)
+}
+
+type firstCorrectPolicy struct{}
+
+func (firstCorrectPolicy) Points(response ScoredResponse) int {
+	points := classicPolicy{}.Points(response)
+	if response.FirstCorrect && response.IsCorrect() {
+		points += firstCorrectBonus
+	}
+	return points
+}
+
+func (firstCorrectPolicy) Description() string {
+	return fmt.Sprintf("%d points for a correct answer, plus up to %d for answering quickly. "+
+		"The first correct answer each round wins %d more!", correctPoints, maxSpeedPoints, firstCorrectBonus)
+}
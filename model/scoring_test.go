@@ -0,0 +1,80 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScoringPolicies(t *testing.T) {
+	allowed := 10 * time.Second
+	correct := ScoredResponse{Accuracy: 1, Elapsed: 5 * time.Second, Allowed: allowed}
+	wrong := ScoredResponse{Accuracy: 0, Elapsed: 5 * time.Second, Allowed: allowed}
+	late := ScoredResponse{Accuracy: 1, Elapsed: 11 * time.Second, Allowed: allowed}
+	onStreak := correct
+	onStreak.Streak = 2
+	first := correct
+	first.FirstCorrect = true
+
+	tests := []struct {
+		policy   ScoringPolicyName
+		response ScoredResponse
+		expected int
+	}{
+		{ClassicScoring, correct, 125},
+		{ClassicScoring, wrong, 25},
+		{ClassicScoring, late, 0},
+		{AccuracyScoring, correct, 100},
+		{AccuracyScoring, wrong, 0},
+		{NegativeScoring, wrong, -50},
+		{NegativeScoring, late, 0},
+		{StreakScoring, onStreak, 187},
+		{StreakScoring, wrong, 25},
+		{FirstCorrectScoring, first, 175},
+		{FirstCorrectScoring, correct, 125},
+	}
+
+	for _, test := range tests {
+		policy, err := ScoringPolicyByName(test.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := policy.Points(test.response)
+		if got != test.expected {
+			t.Errorf("%s scored %+v as %d and expected %d", test.policy, test.response, got, test.expected)
+		}
+	}
+}
+
+func TestUnknownScoringPolicy(t *testing.T) {
+	game := Game{}
+	err := game.ApplyRules(SetRules{ScoringPolicy: "BOGUS"})
+	if err == nil {
+		t.Error("Expected an error for an unknown scoring policy")
+	}
+}
+
+func TestStreakBonus(t *testing.T) {
+	game := Game{StreakBonus: 10, MaxStreakBonus: 25, SecondsPerQuestion: 10}
+	correct := ScoredResponse{Accuracy: 1, Elapsed: 5 * time.Second, Allowed: 10 * time.Second}
+
+	for streak, expected := range []int{125, 135, 145, 150, 150} {
+		correct.Streak = streak
+		got := game.CalculatePoints(correct)
+		if got != expected {
+			t.Errorf("Streak of %d: got %d and expected %d", streak, got, expected)
+		}
+	}
+
+	wrong := ScoredResponse{Accuracy: 0, Elapsed: 5 * time.Second, Allowed: 10 * time.Second, Streak: 3}
+	if got := game.CalculatePoints(wrong); got != 25 {
+		t.Errorf("Wrong answer on a streak: got %d and expected %d", got, 25)
+	}
+
+	// The streak policy already rewards streaks, so the bonus isn't added on top
+	game.ScoringPolicy = StreakScoring
+	correct.Streak = 2
+	withoutBonus := Game{ScoringPolicy: StreakScoring, SecondsPerQuestion: 10}
+	if got, expected := game.CalculatePoints(correct), withoutBonus.CalculatePoints(correct); got != expected {
+		t.Errorf("Streak policy: got %d and expected %d", got, expected)
+	}
+}
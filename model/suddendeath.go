@@ -0,0 +1,49 @@
+package model
+
+// NeedsSuddenDeath returns true if the game is over but the players in first place can only be
+// separated by a sudden-death round
+func (game *Game) NeedsSuddenDeath(players Players) bool {
+	if game.TieBreaker != SuddenDeathTieBreaker || game.IsTeamRace() {
+		return false
+	}
+	for _, p := range players {
+		if p.WonSuddenDeath {
+			return false
+		}
+	}
+	return len(game.tiedForFirst(players)) > 1
+}
+
+// StartSuddenDeath makes the next round a sudden-death round for the players tied for first.
+// Everyone else watches. Returns the players in the sudden-death round.
+func (game *Game) StartSuddenDeath(players Players) Players {
+	tied := game.tiedForFirst(players)
+	inSuddenDeath := make(map[string]bool, len(tied))
+	for _, p := range tied {
+		inSuddenDeath[p.ConnectionId] = true
+	}
+
+	for _, p := range players {
+		p.Spectating = !inSuddenDeath[p.ConnectionId]
+	}
+	game.SuddenDeath = true
+	return tied
+}
+
+// tiedForFirst returns the players still in the game who share first place
+func (game *Game) tiedForFirst(players Players) Players {
+	var first *Player
+	for _, p := range players {
+		if first == nil || compareFinish(p, first, game.TieBreaker) < 0 {
+			first = p
+		}
+	}
+
+	tied := make(Players, 0)
+	for _, p := range players {
+		if p.Active && !p.Eliminated && compareFinish(p, first, game.TieBreaker) == 0 {
+			tied = append(tied, p)
+		}
+	}
+	return tied
+}
@@ -0,0 +1,44 @@
+package model
+
+import "testing"
+
+func TestSuddenDeath(t *testing.T) {
+	game := Game{TieBreaker: SuddenDeathTieBreaker}
+	players := Players{
+		{ConnectionId: "a", Name: "Ann", Active: true, Points: 500},
+		{ConnectionId: "b", Name: "Bob", Active: true, Points: 500},
+		{ConnectionId: "c", Name: "Cat", Active: true, Points: 300},
+	}
+
+	if !game.NeedsSuddenDeath(players) {
+		t.Fatal("Expected sudden death between the players tied for first")
+	}
+	inSuddenDeath := game.StartSuddenDeath(players)
+	if len(inSuddenDeath) != 2 || !game.SuddenDeath {
+		t.Errorf("Got %d players in sudden death", len(inSuddenDeath))
+	}
+	if !players[0].IsRacing() || !players[1].IsRacing() || players[2].IsRacing() {
+		t.Error("Only the tied players should be racing")
+	}
+
+	players[1].WonSuddenDeath = true
+	if game.NeedsSuddenDeath(players) {
+		t.Error("No more sudden death once it has been won")
+	}
+	rankings := players.Rankings(game.TieBreaker)
+	if rankings[0].Name != "Bob" || rankings[0].Tied || rankings[1].Name != "Ann" {
+		t.Errorf("Got rankings %+v", rankings)
+	}
+}
+
+func TestNoSuddenDeath(t *testing.T) {
+	untied := Players{{Active: true, Points: 500}, {Active: true, Points: 400}}
+	tied := Players{{Active: true, Points: 500}, {Active: true, Points: 500}}
+
+	if (&Game{TieBreaker: SuddenDeathTieBreaker}).NeedsSuddenDeath(untied) {
+		t.Error("Didn't expect sudden death without a tie")
+	}
+	if (&Game{TieBreaker: FastestTieBreaker}).NeedsSuddenDeath(tied) {
+		t.Error("Didn't expect sudden death with another tie-breaker")
+	}
+}
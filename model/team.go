@@ -0,0 +1,152 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TeamScoring decides how the points of a team's members make up the team's score
+type TeamScoring string
+
+const (
+	// SumTeamScoring adds up the points of every member
+	SumTeamScoring = TeamScoring("SUM")
+	// AverageTeamScoring averages the points of the members, so small teams aren't disadvantaged
+	AverageTeamScoring = TeamScoring("AVERAGE")
+)
+
+// IsValid returns true if the team scoring is known
+func (scoring TeamScoring) IsValid() bool {
+	return scoring == SumTeamScoring || scoring == AverageTeamScoring
+}
+
+// validateTeams checks there are at least two teams with distinct names, or none at all
+func validateTeams(teams []string) error {
+	if len(teams) == 1 {
+		return errors.New("a team race needs at least two teams")
+	}
+	seen := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		name := strings.ToLower(strings.TrimSpace(team))
+		if name == "" {
+			return errors.New("teams need a name")
+		}
+		if seen[name] {
+			return fmt.Errorf("there is more than one team called %s", team)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// IsTeamRace returns true if players race in teams
+func (game *Game) IsTeamRace() bool {
+	return len(game.Teams) > 0
+}
+
+// HasTeam returns true if the team is one of the game's teams
+func (game *Game) HasTeam(team string) bool {
+	for _, name := range game.Teams {
+		if name == team {
+			return true
+		}
+	}
+	return false
+}
+
+// AssignTeam returns the team for a player joining the game. Players get the team they asked
+// for if there is one, otherwise they join the team with the fewest members.
+func (game *Game) AssignTeam(players Players, requested string) string {
+	if !game.IsTeamRace() {
+		return ""
+	}
+	if game.HasTeam(requested) {
+		return requested
+	}
+
+	smallest := game.Teams[0]
+	members := players.TeamSizes()
+	for _, team := range game.Teams[1:] {
+		if members[team] < members[smallest] {
+			smallest = team
+		}
+	}
+	return smallest
+}
+
+// AssignTeams puts players who aren't in one of the game's teams into one, or takes players out
+// of their team if the game no longer has teams. Returns true if any player changed team.
+func (game *Game) AssignTeams(players Players) bool {
+	changed := false
+	for _, p := range players {
+		if game.HasTeam(p.Team) || (p.Team == "" && !game.IsTeamRace()) {
+			continue
+		}
+		// Leave the old team first so it isn't counted when picking the smallest team
+		p.Team = ""
+		p.Team = game.AssignTeam(players, "")
+		changed = true
+	}
+	return changed
+}
+
+// TeamSizes returns the number of players in each team
+func (players Players) TeamSizes() map[string]int {
+	sizes := make(map[string]int)
+	for _, p := range players {
+		if p.Team != "" {
+			sizes[p.Team]++
+		}
+	}
+	return sizes
+}
+
+// TeamStates returns the score of each of the game's teams, in the order the teams were set up
+func (game *Game) TeamStates(players Players) []TeamState {
+	teamStates := make([]TeamState, 0, len(game.Teams))
+	for _, team := range game.Teams {
+		teamState := TeamState{Name: team}
+		points := 0
+		for _, p := range players {
+			if p.Team == team {
+				teamState.Members++
+				points += p.Points
+			}
+		}
+		teamState.Score = points
+		if game.TeamScoring == AverageTeamScoring && teamState.Members > 0 {
+			teamState.Score = points / teamState.Members
+		}
+		teamStates = append(teamStates, teamState)
+	}
+	return teamStates
+}
+
+// LeadingTeam returns the team with the highest score. They may not have actually won yet.
+func (game *Game) LeadingTeam(players Players) TeamState {
+	teamStates := game.TeamStates(players)
+	if len(teamStates) == 0 {
+		return TeamState{}
+	}
+	leader := teamStates[0]
+	for _, teamState := range teamStates[1:] {
+		if teamState.Score > leader.Score {
+			leader = teamState
+		}
+	}
+	return leader
+}
+
+// LeadingScore returns the highest score of a team in team races, otherwise of a player. The
+// game is won when this reaches the target score.
+func (game *Game) LeadingScore(players Players) int {
+	if game.IsTeamRace() {
+		return game.LeadingTeam(players).Score
+	}
+	leader := players.PlayerWithHighestPoints()
+	if leader == nil {
+		return 0
+	}
+	return leader.Points
+}
@@ -0,0 +1,81 @@
+package model
+
+import "testing"
+
+func TestAssignTeam(t *testing.T) {
+	game := Game{Teams: []string{"Sales", "Engineering"}}
+	players := Players{{Team: "Sales"}, {Team: "Sales"}, {Team: "Engineering"}}
+
+	if got := game.AssignTeam(players, "Sales"); got != "Sales" {
+		t.Errorf("Got %q and expected the requested team", got)
+	}
+	if got := game.AssignTeam(players, "Marketing"); got != "Engineering" {
+		t.Errorf("Got %q and expected the smallest team", got)
+	}
+}
+
+func TestAssignTeamsAfterTeamsChange(t *testing.T) {
+	game := Game{Teams: []string{"Red", "Blue"}}
+	players := Players{{Team: "Sales"}, {Team: "Red"}, {}}
+
+	if !game.AssignTeams(players) {
+		t.Error("Expected players to change team")
+	}
+	sizes := players.TeamSizes()
+	if sizes["Red"] != 2 || sizes["Blue"] != 1 {
+		t.Errorf("Got team sizes %v", sizes)
+	}
+
+	game.Teams = []string{}
+	game.AssignTeams(players)
+	if len(players.TeamSizes()) != 0 {
+		t.Errorf("Expected no teams but got %v", players.TeamSizes())
+	}
+}
+
+func TestTeamScores(t *testing.T) {
+	players := Players{{Team: "Red", Points: 300}, {Team: "Red", Points: 100}, {Team: "Blue", Points: 250}}
+	tests := []struct {
+		scoring       TeamScoring
+		expectedScore int
+		expectedTeam  string
+	}{
+		{SumTeamScoring, 400, "Red"},
+		{AverageTeamScoring, 250, "Blue"},
+	}
+
+	for _, test := range tests {
+		game := Game{Teams: []string{"Blue", "Red"}, TeamScoring: test.scoring}
+		leader := game.LeadingTeam(players)
+		if leader.Name != test.expectedTeam || game.LeadingScore(players) != test.expectedScore {
+			t.Errorf("%s: got %s with %d and expected %s with %d",
+				test.scoring, leader.Name, leader.Score, test.expectedTeam, test.expectedScore)
+		}
+	}
+}
+
+func TestInvalidTeams(t *testing.T) {
+	for _, teams := range [][]string{{"Solo"}, {"Red", "red"}, {"Red", " "}} {
+		game := Game{}
+		if err := game.ApplyRules(SetRules{Teams: teams}); err == nil {
+			t.Errorf("Expected an error for teams %v", teams)
+		}
+	}
+}
+
+func TestLeadingScoreWhenEveryoneIsNegative(t *testing.T) {
+	players := Players{{Team: "Red", Points: -40}, {Team: "Blue", Points: -15}, {Team: "Red", Points: -30}}
+
+	game := Game{}
+	if got := game.LeadingScore(players); got != -15 {
+		t.Errorf("Got leading score %d and expected -15", got)
+	}
+	if got := game.LeadingScore(Players{}); got != 0 {
+		t.Errorf("Got leading score %d with no players and expected 0", got)
+	}
+
+	game = Game{Teams: []string{"Red", "Blue"}, TeamScoring: SumTeamScoring}
+	if leader := game.LeadingTeam(players); leader.Name != "Blue" || leader.Score != -15 {
+		t.Errorf("Got leading team %s with %d and expected Blue with -15", leader.Name, leader.Score)
+	}
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// thesaurusListSeparator separates the words in the synonyms and antonyms columns
+const thesaurusListSeparator = "|"
+
+// Thesaurus holds words related to each word, keyed by the lower case word
+type Thesaurus map[string]ThesaurusEntry
+
+// ThesaurusEntry holds the words related to one word
+type ThesaurusEntry struct {
+	Synonyms []string
+	Antonyms []string
+}
+
+// ReadThesaurus reads a CSV thesaurus with a "word,synonyms,antonyms" header, where synonyms and
+// antonyms are separated by "|". Malformed rows are skipped and returned as errors.
+func ReadThesaurus(reader io.Reader) (Thesaurus, WordErrors) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	thesaurus := make(Thesaurus)
+	wordErrors := WordErrors{}
+	line := 0
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			var parseError *csv.ParseError
+			if !errors.As(err, &parseError) {
+				wordErrors = append(wordErrors, WordError{Line: line, Message: err.Error()})
+				break
+			}
+			wordErrors = append(wordErrors, WordError{Line: parseError.StartLine, Message: parseError.Err.Error()})
+			continue
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "word") {
+			continue
+		}
+		if len(record) < 2 || strings.TrimSpace(record[0]) == "" {
+			wordErrors = append(wordErrors, WordError{Line: line, Message: "expected a word and its synonyms"})
+			continue
+		}
+
+		entry := ThesaurusEntry{
+			Synonyms: splitThesaurusList(record[1]),
+		}
+		if len(record) > 2 {
+			entry.Antonyms = splitThesaurusList(record[2])
+		}
+		thesaurus[strings.ToLower(strings.TrimSpace(record[0]))] = entry
+	}
+	return thesaurus, wordErrors
+}
+
+func splitThesaurusList(list string) []string {
+	words := make([]string, 0)
+	for _, word := range strings.Split(list, thesaurusListSeparator) {
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// ApplyThesaurus adds synonyms and antonyms from the thesaurus to the words that don't have any
+func (words Words) ApplyThesaurus(thesaurus Thesaurus) {
+	for i := range words {
+		entry, present := thesaurus[strings.ToLower(words[i].Word)]
+		if !present {
+			continue
+		}
+		if len(words[i].Synonyms) == 0 {
+			words[i].Synonyms = entry.Synonyms
+		}
+		if len(words[i].Antonyms) == 0 {
+			words[i].Antonyms = entry.Antonyms
+		}
+	}
+}
+
+// FilterByRelatedWords returns the words that have synonyms in synonym rounds, or antonyms in
+// antonym rounds
+func (words Words) FilterByRelatedWords(mode QuestionMode) Words {
+	filteredWords := make(Words, 0, len(words))
+	for _, word := range words {
+		if (mode == SynonymMode && len(word.Synonyms) > 0) || (mode == AntonymMode && len(word.Antonyms) > 0) {
+			filteredWords = append(filteredWords, word)
+		}
+	}
+	return filteredWords
+}
+
+// IsRelatedTo returns true if the other word is the word itself, or one of its synonyms or antonyms
+func (d Word) IsRelatedTo(otherWord string) bool {
+	if strings.EqualFold(d.Word, otherWord) {
+		return true
+	}
+	for _, relatedWord := range append(append([]string{}, d.Synonyms...), d.Antonyms...) {
+		if strings.EqualFold(relatedWord, otherWord) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnrelatedTo returns the words that aren't the word or one of its synonyms or antonyms
+func (words Words) UnrelatedTo(word Word) Words {
+	unrelatedWords := make(Words, 0, len(words))
+	for _, otherWord := range words {
+		if !word.IsRelatedTo(otherWord.Word) {
+			unrelatedWords = append(unrelatedWords, otherWord)
+		}
+	}
+	return unrelatedWords
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestReadThesaurus(t *testing.T) {
+	content := "word,synonyms,antonyms\nLaconic,terse|concise,verbose\n,missing\n"
+	thesaurus, wordErrors := ReadThesaurus(strings.NewReader(content))
+	if len(wordErrors) != 1 || wordErrors[0].Line != 3 {
+		t.Errorf("Got errors %v and expected one on line 3", wordErrors)
+	}
+
+	words := Words{Word{Word: "laconic", WordType: "adjective"}}
+	words.ApplyThesaurus(thesaurus)
+	if len(words[0].Synonyms) != 2 || words[0].Antonyms[0] != "verbose" {
+		t.Errorf("Got synonyms %v and antonyms %v", words[0].Synonyms, words[0].Antonyms)
+	}
+}
+
+func TestNewRelatedWordQuestion(t *testing.T) {
+	word := Word{Word: "laconic", WordType: "adjective", Synonyms: []string{"terse"}, Antonyms: []string{"verbose"}}
+	candidates := Words{
+		Word{Word: "verbose", WordType: "adjective"},
+		Word{Word: "happy", WordType: "adjective"},
+		Word{Word: "dark", WordType: "adjective"},
+	}
+
+	distractors := candidates.UnrelatedTo(word)
+	got := NewRelatedWordQuestion(rand.New(rand.NewSource(1)), SynonymMode, word, distractors, DefaultLanguage)
+	if len(got.Options) != 3 {
+		t.Fatalf("Got %d options and expected %d", len(got.Options), 3)
+	}
+	if got.Options[got.CorrectAnswer] != "terse" {
+		t.Errorf("Got correct option %s and expected %s", got.Options[got.CorrectAnswer], "terse")
+	}
+}
@@ -0,0 +1,305 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Tournament is a bracket of games. Entrants are seeded into heats, and the top players of each
+// heat advance to the next round until a final heat decides the winner. The JSON metadata is for
+// converting this struct into a DynamoDB item.
+type Tournament struct {
+	TournamentId string          `json:"tournament_id"`
+	Name         string          `json:"name"`
+	State        TournamentState `json:"tournament_state"`
+	// The most players in one heat, and how many of them advance to the next round
+	HeatSize       int `json:"heat_size"`
+	AdvancePerHeat int `json:"advance_per_heat"`
+	// Players registered to play, and the connection ids of those watching
+	Entrants   []Entrant `json:"entrants"`
+	Spectators []string  `json:"spectators"`
+	// The heats of each round played so far
+	Rounds []BracketRound `json:"rounds"`
+	// The connection id of the winner once the final heat is over
+	Winner string `json:"winner"`
+	// Incremented on every save so concurrent updates can't overwrite each other
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt int64     `json:"expires_at"`
+}
+
+type TournamentState string
+
+const (
+	TournamentRegistering = TournamentState("REGISTERING")
+	TournamentInProgress  = TournamentState("IN_PROGRESS")
+	TournamentFinished    = TournamentState("FINISHED")
+)
+
+// Entrant is a player registered for a tournament
+type Entrant struct {
+	ConnectionId string `json:"connection_id"`
+	Name         string `json:"name"`
+	Icon         string `json:"icon"`
+	// Whether the player's connection has gone, so they can't be seeded into another heat
+	Withdrawn bool `json:"withdrawn"`
+}
+
+// BracketRound is one round of a tournament
+type BracketRound struct {
+	Heats []Heat `json:"heats"`
+}
+
+// Heat is one game in a round of a tournament
+type Heat struct {
+	GameId string `json:"game_id"`
+	// Connection ids of the players seeded into the heat, and of those who advanced from it
+	Entrants []string `json:"entrants"`
+	Advanced []string `json:"advanced"`
+	Finished bool     `json:"finished"`
+}
+
+// NewTournament creates a tournament open for registration
+func NewTournament(tournamentId string, name string, heatSize int, advancePerHeat int) (*Tournament, error) {
+	if heatSize < 2 {
+		return nil, errors.New("heats need at least two players")
+	}
+	if advancePerHeat < 1 || advancePerHeat >= heatSize {
+		return nil, fmt.Errorf("between 1 and %d players can advance from each heat", heatSize-1)
+	}
+	return &Tournament{
+		TournamentId:   tournamentId,
+		Name:           name,
+		State:          TournamentRegistering,
+		HeatSize:       heatSize,
+		AdvancePerHeat: advancePerHeat,
+		Entrants:       make([]Entrant, 0),
+		Spectators:     make([]string, 0),
+		Rounds:         make([]BracketRound, 0),
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// Register adds a player to the tournament. Registering again updates their name and icon.
+func (t *Tournament) Register(entrant Entrant) error {
+	if t.State != TournamentRegistering {
+		return errors.New("registration for the tournament has closed")
+	}
+	for i, registered := range t.Entrants {
+		if registered.ConnectionId == entrant.ConnectionId {
+			t.Entrants[i] = entrant
+			return nil
+		}
+	}
+	t.Entrants = append(t.Entrants, entrant)
+	return nil
+}
+
+// Spectate adds a connection to those told about bracket updates
+func (t *Tournament) Spectate(connectionId string) {
+	for _, spectator := range t.Spectators {
+		if spectator == connectionId {
+			return
+		}
+	}
+	t.Spectators = append(t.Spectators, connectionId)
+}
+
+// Entrant returns the registered player with the connection id
+func (t *Tournament) Entrant(connectionId string) (Entrant, bool) {
+	for _, entrant := range t.Entrants {
+		if entrant.ConnectionId == connectionId {
+			return entrant, true
+		}
+	}
+	return Entrant{}, false
+}
+
+// Withdraw stops a player whose connection has gone from being seeded into another heat
+func (t *Tournament) Withdraw(connectionId string) {
+	for i := range t.Entrants {
+		if t.Entrants[i].ConnectionId == connectionId {
+			t.Entrants[i].Withdrawn = true
+		}
+	}
+}
+
+// isWithdrawn returns true if the player with the connection id has withdrawn
+func (t *Tournament) isWithdrawn(connectionId string) bool {
+	entrant, _ := t.Entrant(connectionId)
+	return entrant.Withdrawn
+}
+
+// Participants returns the connection ids of everyone following the tournament
+func (t *Tournament) Participants() []string {
+	participants := make([]string, 0, len(t.Entrants)+len(t.Spectators))
+	for _, entrant := range t.Entrants {
+		if !entrant.Withdrawn {
+			participants = append(participants, entrant.ConnectionId)
+		}
+	}
+	return append(participants, t.Spectators...)
+}
+
+// CurrentRound returns the round being played, or nil if the tournament hasn't started
+func (t *Tournament) CurrentRound() *BracketRound {
+	if len(t.Rounds) == 0 {
+		return nil
+	}
+	return &t.Rounds[len(t.Rounds)-1]
+}
+
+// Start closes registration and seeds the entrants into the first round of heats
+func (t *Tournament) Start() error {
+	if t.State != TournamentRegistering {
+		return errors.New("the tournament has already started")
+	}
+	connectionIds := make([]string, 0, len(t.Entrants))
+	for _, entrant := range t.Entrants {
+		if !entrant.Withdrawn {
+			connectionIds = append(connectionIds, entrant.ConnectionId)
+		}
+	}
+	if len(connectionIds) < 2 {
+		return errors.New("a tournament needs at least two players")
+	}
+	t.State = TournamentInProgress
+	t.seedRound(connectionIds)
+	return nil
+}
+
+// RecordHeatResult advances the top players of a finished heat, given every player's connection
+// id from first to last. Players who have withdrawn don't advance. At least one player in a heat
+// is knocked out, unless they are alone in it. Once every heat in the round has finished, the
+// next round is seeded or the tournament is won. Returns false if the heat isn't in the current
+// round or has already been recorded.
+func (t *Tournament) RecordHeatResult(gameId string, finishingOrder []string) bool {
+	round := t.CurrentRound()
+	if t.State != TournamentInProgress || round == nil {
+		return false
+	}
+
+	var heat *Heat
+	for i := range round.Heats {
+		if round.Heats[i].GameId == gameId {
+			heat = &round.Heats[i]
+		}
+	}
+	if heat == nil || heat.Finished {
+		return false
+	}
+
+	advancing := t.AdvancePerHeat
+	if advancing > len(heat.Entrants)-1 {
+		advancing = len(heat.Entrants) - 1
+	}
+	if advancing < 1 {
+		advancing = 1
+	}
+	heat.Advanced = make([]string, 0, advancing)
+	for _, connectionId := range finishingOrder {
+		if len(heat.Advanced) < advancing && containsString(heat.Entrants, connectionId) && !t.isWithdrawn(connectionId) {
+			heat.Advanced = append(heat.Advanced, connectionId)
+		}
+	}
+	heat.Finished = true
+
+	advanced := make([]string, 0)
+	seeded := 0
+	for _, heat := range round.Heats {
+		if !heat.Finished {
+			return true
+		}
+		advanced = append(advanced, heat.Advanced...)
+		seeded += len(heat.Entrants)
+	}
+
+	// The final heat decides the winner
+	if len(round.Heats) == 1 || len(advanced) < 2 {
+		t.State = TournamentFinished
+		if len(advanced) > 0 {
+			t.Winner = advanced[0]
+		}
+		return true
+	}
+	// Every round must have fewer players than the last, or the tournament would never finish
+	if len(advanced) >= seeded {
+		t.seedFinal(advanced)
+		return true
+	}
+	t.seedRound(advanced)
+	return true
+}
+
+// seedRound spreads the players across as few heats as possible. Players are dealt out in turn
+// so the top seeds are kept apart.
+func (t *Tournament) seedRound(connectionIds []string) {
+	numberOfHeats := (len(connectionIds) + t.HeatSize - 1) / t.HeatSize
+	t.seedHeats(connectionIds, numberOfHeats)
+}
+
+// seedFinal puts all the players into one heat to decide the winner
+func (t *Tournament) seedFinal(connectionIds []string) {
+	t.seedHeats(connectionIds, 1)
+}
+
+func (t *Tournament) seedHeats(connectionIds []string, numberOfHeats int) {
+	heats := make([]Heat, numberOfHeats)
+	for i := range heats {
+		heats[i] = Heat{
+			GameId:   fmt.Sprintf("%s-round-%d-heat-%d", t.TournamentId, len(t.Rounds)+1, i+1),
+			Entrants: make([]string, 0, t.HeatSize),
+			Advanced: make([]string, 0),
+		}
+	}
+	for i, connectionId := range connectionIds {
+		heat := &heats[i%numberOfHeats]
+		heat.Entrants = append(heat.Entrants, connectionId)
+	}
+	t.Rounds = append(t.Rounds, BracketRound{Heats: heats})
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Bracket describes the tournament for players and spectators, using player names
+func (t *Tournament) Bracket() Bracket {
+	name := func(connectionId string) string {
+		entrant, _ := t.Entrant(connectionId)
+		return entrant.Name
+	}
+	names := func(connectionIds []string) []string {
+		result := make([]string, len(connectionIds))
+		for i, connectionId := range connectionIds {
+			result[i] = name(connectionId)
+		}
+		return result
+	}
+
+	bracket := Bracket{
+		TournamentId: t.TournamentId,
+		Name:         t.Name,
+		State:        t.State,
+		Entrants:     len(t.Entrants),
+		Rounds:       make([][]BracketHeat, len(t.Rounds)),
+		Winner:       name(t.Winner),
+	}
+	for i, round := range t.Rounds {
+		bracket.Rounds[i] = make([]BracketHeat, len(round.Heats))
+		for j, heat := range round.Heats {
+			bracket.Rounds[i][j] = BracketHeat{
+				Players:  names(heat.Entrants),
+				Advanced: names(heat.Advanced),
+				Finished: heat.Finished,
+			}
+		}
+	}
+	return bracket
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTournament(t *testing.T) {
+	tournament, err := NewTournament("t", "Offsite", 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 7; i++ {
+		err := tournament.Register(Entrant{ConnectionId: fmt.Sprint(i), Name: fmt.Sprint("Player ", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tournament.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if tournament.Register(Entrant{ConnectionId: "late"}) == nil {
+		t.Error("Expected registration to be closed")
+	}
+
+	// Seven players in heats of three need three heats
+	heats := tournament.CurrentRound().Heats
+	if len(heats) != 3 || len(heats[0].Entrants) != 3 || len(heats[2].Entrants) != 2 {
+		t.Fatalf("Got heats %+v", heats)
+	}
+
+	// The winner of each heat meets in the final
+	for _, heat := range heats {
+		if !tournament.RecordHeatResult(heat.GameId, heat.Entrants) {
+			t.Errorf("Expected %s to be recorded", heat.GameId)
+		}
+	}
+	if tournament.RecordHeatResult(heats[0].GameId, heats[0].Entrants) {
+		t.Error("Expected a heat to be recorded only once")
+	}
+	if len(tournament.Rounds) != 2 {
+		t.Fatalf("Got %d rounds and expected 2", len(tournament.Rounds))
+	}
+	final := tournament.CurrentRound().Heats
+	if len(final) != 1 || len(final[0].Entrants) != 3 {
+		t.Fatalf("Got final %+v", final)
+	}
+
+	tournament.RecordHeatResult(final[0].GameId, []string{"3", "1", "2"})
+	if tournament.State != TournamentFinished || tournament.Winner != "3" {
+		t.Errorf("Got state %s and winner %s", tournament.State, tournament.Winner)
+	}
+	if bracket := tournament.Bracket(); bracket.Winner != "Player 3" || len(bracket.Rounds) != 2 {
+		t.Errorf("Got bracket %+v", bracket)
+	}
+}
+
+func TestInvalidTournament(t *testing.T) {
+	if _, err := NewTournament("t", "Offsite", 4, 4); err == nil {
+		t.Error("Expected an error when everyone advances")
+	}
+}
+
+func TestTournament_EveryRoundKnocksPlayersOut(t *testing.T) {
+	// Two heats of two would advance everyone if two players advanced from each heat
+	tournament, err := NewTournament("t", "Offsite", 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 4; i++ {
+		_ = tournament.Register(Entrant{ConnectionId: fmt.Sprint(i), Name: fmt.Sprint("Player ", i)})
+	}
+	if err := tournament.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for rounds := 1; tournament.State != TournamentFinished; rounds++ {
+		if rounds > 4 {
+			t.Fatalf("Tournament still in progress after %d rounds: %+v", rounds-1, tournament.Rounds)
+		}
+		for _, heat := range tournament.CurrentRound().Heats {
+			tournament.RecordHeatResult(heat.GameId, heat.Entrants)
+		}
+	}
+	if len(tournament.Rounds) != 2 || tournament.Winner != "1" {
+		t.Errorf("Got %d rounds and winner %s", len(tournament.Rounds), tournament.Winner)
+	}
+}
+
+func TestTournament_Withdraw(t *testing.T) {
+	tournament, err := NewTournament("t", "Offsite", 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 4; i++ {
+		_ = tournament.Register(Entrant{ConnectionId: fmt.Sprint(i), Name: fmt.Sprint("Player ", i)})
+	}
+	tournament.Withdraw("4")
+	if err := tournament.Start(); err != nil {
+		t.Fatal(err)
+	}
+	for _, heat := range tournament.CurrentRound().Heats {
+		if containsString(heat.Entrants, "4") {
+			t.Errorf("Expected a withdrawn player to be left out of heat %+v", heat)
+		}
+	}
+
+	// A player who leaves during a heat doesn't advance from it, even with the most points
+	players := Players{
+		{ConnectionId: "1", Active: false, Points: 500},
+		{ConnectionId: "3", Active: true, Points: 100},
+	}
+	tournament.Withdraw("1")
+	heat := tournament.CurrentRound().Heats[0]
+	tournament.RecordHeatResult(heat.GameId, players.FinishingOrder(NoTieBreaker))
+	if advanced := tournament.CurrentRound().Heats[0].Advanced; len(advanced) != 1 || advanced[0] != "3" {
+		t.Errorf("Got advanced %v and expected [3]", advanced)
+	}
+}
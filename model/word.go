@@ -1,27 +1,120 @@
 package model
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type Word struct {
 	Word       string
 	WordType   string
 	Definition string
-	URL        string
+	URL        string `json:",omitempty"`
+	// Optional: how hard the word is, from 1 (easy) upwards. Zero when unknown.
+	Difficulty int `json:",omitempty"`
+	// Optional: the word pack this word belongs to
+	Pack string `json:",omitempty"`
+	// Optional: the language of the word
+	Language string `json:",omitempty"`
+	// Optional: sentences using the word. Only kept in the JSON formats.
+	Examples []string `json:",omitempty"`
+	// Optional: how the word is pronounced. Only kept in the JSON formats.
+	Pronunciation string `json:",omitempty"`
+	// Optional: labels for grouping words. Only kept in the JSON formats.
+	Tags []string `json:",omitempty"`
+	// Optional: the word in other languages, keyed by language. Only kept in the JSON formats.
+	Translations map[string]string `json:",omitempty"`
+	// Optional: words with the same meaning. Only kept in the JSON formats.
+	Synonyms []string `json:",omitempty"`
+	// Optional: words with the opposite meaning. Only kept in the JSON formats.
+	Antonyms []string `json:",omitempty"`
 }
 
-func NewWord(stringSlice []string) Word {
-	return Word{
-		Word:       stringSlice[0],
-		WordType:   stringSlice[1],
-		Definition: stringSlice[2],
-		URL:        stringSlice[3],
+// WordColumns are the CSV column names, in the order written by ToStringSlice
+var WordColumns = []string{"word", "type", "definition", "url", "difficulty", "pack", "language"}
+
+// requiredColumns is the number of leading columns every CSV row must have
+const requiredColumns = 3
+
+// NewWord creates a word from a CSV row in the ToStringSlice column layout. Only the word, type
+// and definition columns are required.
+func NewWord(stringSlice []string) (Word, error) {
+	return newWordFromColumns(stringSlice, positionalColumns)
+}
+
+// positionalColumns maps each column name to its position in WordColumns
+var positionalColumns = func() map[string]int {
+	columnIndexes := make(map[string]int, len(WordColumns))
+	for i, column := range WordColumns {
+		columnIndexes[column] = i
+	}
+	return columnIndexes
+}()
+
+// newWordFromColumns creates a word from a CSV row, looking up each field with the column indexes
+func newWordFromColumns(stringSlice []string, columnIndexes map[string]int) (Word, error) {
+	if len(stringSlice) < requiredColumns {
+		return Word{}, fmt.Errorf("expected at least %d columns but got %d", requiredColumns, len(stringSlice))
 	}
+
+	column := func(name string) string {
+		index, present := columnIndexes[name]
+		if !present || index >= len(stringSlice) {
+			return ""
+		}
+		return strings.TrimSpace(stringSlice[index])
+	}
+
+	word := Word{
+		Word:       column("word"),
+		WordType:   column("type"),
+		Definition: column("definition"),
+		URL:        column("url"),
+		Pack:       column("pack"),
+		Language:   column("language"),
+	}
+
+	if difficulty := column("difficulty"); difficulty != "" {
+		var err error
+		word.Difficulty, err = strconv.Atoi(difficulty)
+		if err != nil {
+			return Word{}, fmt.Errorf("difficulty %q is not a number", difficulty)
+		}
+	}
+
+	return word, word.Validate()
 }
 
 func (d Word) String() string {
 	return fmt.Sprintf("%s (%s): %s", d.Word, d.WordType, d.Definition)
 }
 
+// HasJSONOnlyFields returns true if the word has fields that can't be kept in CSV
+func (d Word) HasJSONOnlyFields() bool {
+	return len(d.Examples) > 0 || d.Pronunciation != "" || len(d.Tags) > 0 || len(d.Translations) > 0 ||
+		len(d.Synonyms) > 0 || len(d.Antonyms) > 0
+}
+
 func (d Word) ToStringSlice() []string {
-	return []string{d.Word, d.WordType, d.Definition, d.URL}
+	difficulty := ""
+	if d.Difficulty != 0 {
+		difficulty = strconv.Itoa(d.Difficulty)
+	}
+	return []string{d.Word, d.WordType, d.Definition, d.URL, difficulty, d.Pack, d.Language}
+}
+
+// Validate checks the word has everything needed to make a question out of it
+func (d Word) Validate() error {
+	if strings.TrimSpace(d.Word) == "" {
+		return errors.New("missing word")
+	}
+	if strings.TrimSpace(d.WordType) == "" {
+		return fmt.Errorf("missing word type for %q", d.Word)
+	}
+	if strings.TrimSpace(d.Definition) == "" {
+		return fmt.Errorf("missing definition for %q", d.Word)
+	}
+	return nil
 }
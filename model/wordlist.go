@@ -0,0 +1,265 @@
+package model
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WordListFormat is the encoding of an uploaded word list
+type WordListFormat string
+
+const (
+	CSVFormat       = WordListFormat("csv")
+	JSONFormat      = WordListFormat("json")
+	JSONLinesFormat = WordListFormat("jsonl")
+)
+
+// columnAliases are alternate header names accepted for a column
+var columnAliases = map[string]string{
+	"wordtype":  "type",
+	"word_type": "type",
+}
+
+// WordError describes a word list entry that could not be loaded
+type WordError struct {
+	// The line of a CSV word, or the position of a JSON word in its array
+	Line    int
+	Message string
+}
+
+func (e WordError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// WordErrors are all the entries of a word list that could not be loaded
+type WordErrors []WordError
+
+func (e WordErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no errors"
+	case 1:
+		return e[0].Error()
+	default:
+		return fmt.Sprintf("%s (and %d more)", e[0].Error(), len(e)-1)
+	}
+}
+
+// DetectWordListFormat guesses the format of a word list from the start of its content
+func DetectWordListFormat(content string) WordListFormat {
+	trimmedContent := strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(trimmedContent, "["):
+		return JSONFormat
+	case strings.HasPrefix(trimmedContent, "{"):
+		return JSONLinesFormat
+	default:
+		return CSVFormat
+	}
+}
+
+// ReadWordList reads a word list in the given format. If no format is given it is detected
+// from the content. Entries that could not be read are skipped and returned as errors.
+func ReadWordList(format WordListFormat, content string) (Words, WordErrors) {
+	if format == "" {
+		format = DetectWordListFormat(content)
+	}
+
+	return ReadWords(format, strings.NewReader(content))
+}
+
+// ReadWords reads words in the given format. Entries that could not be read are skipped and
+// returned as errors.
+func ReadWords(format WordListFormat, reader io.Reader) (Words, WordErrors) {
+	switch format {
+	case CSVFormat:
+		return ReadWordsCSV(reader)
+	case JSONFormat:
+		return ReadWordsJSON(reader)
+	case JSONLinesFormat:
+		return ReadWordsJSONLines(reader)
+	default:
+		return nil, WordErrors{{Message: fmt.Sprintf("unsupported word list format: %s", format)}}
+	}
+}
+
+// IsWritable returns true if words can be written in the format
+func (format WordListFormat) IsWritable() bool {
+	return format == CSVFormat || format == JSONLinesFormat
+}
+
+// WriteWords writes words in the given format
+func WriteWords(format WordListFormat, writer io.Writer, words Words) error {
+	switch format {
+	case CSVFormat:
+		return WriteWordsCSV(writer, words)
+	case JSONLinesFormat:
+		return WriteWordsJSONLines(writer, words)
+	default:
+		return fmt.Errorf("unsupported word list format: %s", format)
+	}
+}
+
+// ReadWordsCSV reads words in the same column layout as Word.ToStringSlice. If the first row is
+// a header, columns are matched by name instead, and unknown columns are ignored. Malformed rows
+// are skipped and returned as errors.
+func ReadWordsCSV(reader io.Reader) (Words, WordErrors) {
+	csvReader := csv.NewReader(reader)
+	// Rows may have any number of columns, as the optional ones can be left off
+	csvReader.FieldsPerRecord = -1
+
+	words := Words{}
+	wordErrors := WordErrors{}
+	columnIndexes := positionalColumns
+	line := 0
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			var parseError *csv.ParseError
+			if !errors.As(err, &parseError) {
+				// Not a problem with this row, so there is no point reading any further
+				wordErrors = append(wordErrors, WordError{Line: line, Message: err.Error()})
+				break
+			}
+			wordErrors = append(wordErrors, WordError{Line: parseError.StartLine, Message: parseError.Err.Error()})
+			continue
+		}
+
+		if line == 1 && isHeader(record) {
+			columnIndexes = headerColumns(record)
+			continue
+		}
+
+		word, err := newWordFromColumns(record, columnIndexes)
+		if err != nil {
+			wordErrors = append(wordErrors, WordError{Line: line, Message: err.Error()})
+			continue
+		}
+		words = append(words, word)
+	}
+	return words, wordErrors
+}
+
+// ReadWordsJSON reads a JSON array of words. Invalid words are skipped and returned as errors.
+func ReadWordsJSON(reader io.Reader) (Words, WordErrors) {
+	decodedWords := Words{}
+	err := json.NewDecoder(reader).Decode(&decodedWords)
+	if err != nil {
+		return nil, WordErrors{{Message: fmt.Sprintf("error decoding JSON word list: %s", err)}}
+	}
+
+	words := make(Words, 0, len(decodedWords))
+	wordErrors := WordErrors{}
+	for i, word := range decodedWords {
+		if err := word.Validate(); err != nil {
+			wordErrors = append(wordErrors, WordError{Line: i + 1, Message: err.Error()})
+			continue
+		}
+		words = append(words, word)
+	}
+	return words, wordErrors
+}
+
+// ReadWordsJSONLines reads words encoded as one JSON object per line. Malformed lines are
+// skipped and returned as errors.
+func ReadWordsJSONLines(reader io.Reader) (Words, WordErrors) {
+	scanner := bufio.NewScanner(reader)
+	// Allow for words with lots of examples
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLineLength)
+
+	words := Words{}
+	wordErrors := WordErrors{}
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		word := Word{}
+		err := json.Unmarshal(scanner.Bytes(), &word)
+		if err == nil {
+			err = word.Validate()
+		}
+		if err != nil {
+			wordErrors = append(wordErrors, WordError{Line: line, Message: err.Error()})
+			continue
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		wordErrors = append(wordErrors, WordError{Line: line + 1, Message: err.Error()})
+	}
+	return words, wordErrors
+}
+
+// maxJSONLineLength is the longest line ReadWordsJSONLines can read
+const maxJSONLineLength = 1024 * 1024
+
+// WriteWordsCSV writes words with a header row, in the column layout of Word.ToStringSlice.
+// Words with fields only the JSON formats can keep are refused rather than losing those fields.
+func WriteWordsCSV(writer io.Writer, words Words) error {
+	for _, word := range words {
+		if word.HasJSONOnlyFields() {
+			return fmt.Errorf("%s has fields that can only be kept in JSON Lines, not CSV", word.Word)
+		}
+	}
+	csvWriter := csv.NewWriter(writer)
+
+	err := csvWriter.Write(WordColumns)
+	if err != nil {
+		return fmt.Errorf("error writing csv: %w", err)
+	}
+	for _, word := range words {
+		err := csvWriter.Write(word.ToStringSlice())
+		if err != nil {
+			return fmt.Errorf("error writing csv: %w", err)
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// WriteWordsJSONLines writes words as one JSON object per line
+func WriteWordsJSONLines(writer io.Writer, words Words) error {
+	encoder := json.NewEncoder(writer)
+	for _, word := range words {
+		err := encoder.Encode(word)
+		if err != nil {
+			return fmt.Errorf("error writing json: %w", err)
+		}
+	}
+	return nil
+}
+
+// isHeader returns true if the row names the word and definition columns
+func isHeader(record []string) bool {
+	columnIndexes := headerColumns(record)
+	_, hasWord := columnIndexes["word"]
+	_, hasDefinition := columnIndexes["definition"]
+	return hasWord && hasDefinition
+}
+
+// headerColumns maps each column name in a header row to its position
+func headerColumns(header []string) map[string]int {
+	columnIndexes := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if alias, present := columnAliases[name]; present {
+			name = alias
+		}
+		columnIndexes[name] = i
+	}
+	return columnIndexes
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestReadWordList_CSV(t *testing.T) {
+	content := "one,noun,the first,\ntwo,noun,the second,https://example.com/two\n"
+	got, wordErrors := ReadWordList("", content)
+	if len(wordErrors) != 0 {
+		t.Fatalf("Got errors %s", wordErrors)
+	}
+	if len(got) != 2 {
+		t.Errorf("Got length %d and expected %d", len(got), 2)
+	}
+}
+
+func TestReadWordList_JSON(t *testing.T) {
+	content := `[{"Word": "one", "WordType": "noun", "Definition": "the first"}]`
+	got, wordErrors := ReadWordList("", content)
+	if len(wordErrors) != 0 {
+		t.Fatalf("Got errors %s", wordErrors)
+	}
+	if got[0].Word != "one" {
+		t.Errorf("Got word %s and expected %s", got[0].Word, "one")
+	}
+}
+
+func TestReadWordList_MalformedRowsSkipped(t *testing.T) {
+	content := "one,noun,the first,\ntwo,noun\nthree,no\"un,the third\nfour,noun,the fourth,,hard\n"
+	got, wordErrors := ReadWordList(CSVFormat, content)
+	if len(got) != 1 {
+		t.Errorf("Got length %d and expected %d", len(got), 1)
+	}
+	if len(wordErrors) != 3 {
+		t.Fatalf("Got %d errors and expected %d", len(wordErrors), 3)
+	}
+	if wordErrors[0].Line != 2 {
+		t.Errorf("Got line %d and expected %d", wordErrors[0].Line, 2)
+	}
+}
+
+func TestReadWordList_HeaderWithExtraColumns(t *testing.T) {
+	content := "Definition,Word,Type,Language,Difficulty,Notes\nthe first,uno,noun,es,2,ignored\n"
+	got, wordErrors := ReadWordList(CSVFormat, content)
+	if len(wordErrors) != 0 {
+		t.Fatalf("Got errors %s", wordErrors)
+	}
+	expected := Word{Word: "uno", WordType: "noun", Definition: "the first", Language: "es", Difficulty: 2}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], expected) {
+		t.Errorf("Got %v and expected %v", got, expected)
+	}
+}
+
+func TestReadWordList_MissingDefinition(t *testing.T) {
+	content := `[{"Word": "one", "WordType": "noun"}]`
+	_, wordErrors := ReadWordList(JSONFormat, content)
+	if len(wordErrors) != 1 {
+		t.Fatalf("Got %d errors and expected %d", len(wordErrors), 1)
+	}
+}
+
+func TestWriteWords_JSONLinesRoundTrip(t *testing.T) {
+	words := Words{
+		Word{Word: "one", WordType: "noun", Definition: "the first", Examples: []string{"One is enough."}, Tags: []string{"numbers"}},
+		Word{Word: "two", WordType: "noun", Definition: "the second", Pronunciation: "too"},
+	}
+	buffer := &bytes.Buffer{}
+	err := WriteWords(JSONLinesFormat, buffer, words)
+	if err != nil {
+		t.Fatalf("Got error %s", err)
+	}
+
+	format := DetectWordListFormat(buffer.String())
+	if format != JSONLinesFormat {
+		t.Errorf("Got format %s and expected %s", format, JSONLinesFormat)
+	}
+	got, wordErrors := ReadWords(format, buffer)
+	if len(wordErrors) != 0 {
+		t.Fatalf("Got errors %s", wordErrors)
+	}
+	if !reflect.DeepEqual(got, words) {
+		t.Errorf("Got %v and expected %v", got, words)
+	}
+}
+
+func TestWriteWords_CSVRefusesJSONOnlyFields(t *testing.T) {
+	words := Words{Word{Word: "one", WordType: "noun", Definition: "the first", Synonyms: []string{"single"}}}
+	if err := WriteWords(CSVFormat, &bytes.Buffer{}, words); err == nil {
+		t.Error("Expected an error writing synonyms to CSV")
+	}
+	if JSONFormat.IsWritable() {
+		t.Errorf("Expected %s not to be writable", JSONFormat)
+	}
+}
@@ -1,15 +1,44 @@
 package model
 
-import "math/rand"
+import (
+	"math/rand"
+	"sort"
+)
 
 // Words is simply a slice of Word, with handy methods
 type Words []Word
 
+// WordTypes are the main parts of speech
+var WordTypes = []string{"noun", "adjective", "verb", "adverb"}
+
 // PickRandomType returns one of four random word types
 func PickRandomType() string {
-	wordTypes := []string{"noun", "adjective", "verb", "adverb"}
-	randomIndex := rand.Intn(len(wordTypes))
-	return wordTypes[randomIndex]
+	randomIndex := rand.Intn(len(WordTypes))
+	return WordTypes[randomIndex]
+}
+
+// PlayableTypes returns the word types that have at least the minimum number of words, sorted
+func PlayableTypes(wordsByType map[string]Words, minimum int) []string {
+	playableTypes := make([]string, 0, len(wordsByType))
+	for wordType, words := range wordsByType {
+		if len(words) >= minimum {
+			playableTypes = append(playableTypes, wordType)
+		}
+	}
+
+	// Map ordering is random, so sort to keep the pick repeatable for a given seed
+	sort.Strings(playableTypes)
+	return playableTypes
+}
+
+// PickRandomTypeFrom returns a random word type that has at least the minimum number of words.
+// An empty string is returned if no word type has enough words.
+func PickRandomTypeFrom(rng *rand.Rand, wordsByType map[string]Words, minimum int) string {
+	playableTypes := PlayableTypes(wordsByType, minimum)
+	if len(playableTypes) == 0 {
+		return ""
+	}
+	return playableTypes[rng.Intn(len(playableTypes))]
 }
 
 // GroupByType groups this word slice into a map keyed by the type
@@ -25,7 +54,7 @@ func (words Words) GroupByType() map[string]Words {
 
 // PickRandomWords will pick n unique random words from this word slice. If it
 // happens to pick the same word twice, it will re-pick until a unique word is picked.
-func (words Words) PickRandomWords(numberToChoose int) Words {
+func (words Words) PickRandomWords(rng *rand.Rand, numberToChoose int) Words {
 	// Limit the odd case if there just isn't enough words to choose from
 	if numberToChoose >= len(words) {
 		return words
@@ -35,7 +64,7 @@ func (words Words) PickRandomWords(numberToChoose int) Words {
 	pickedIndexes := make(map[int]interface{})
 
 	for len(chosenWords) < numberToChoose {
-		index := words.PickRandomIndex()
+		index := words.PickRandomIndex(rng)
 		if _, present := pickedIndexes[index]; !present {
 			chosenWords = append(chosenWords, words[index])
 			pickedIndexes[index] = struct{}{}
@@ -45,8 +74,54 @@ func (words Words) PickRandomWords(numberToChoose int) Words {
 	return chosenWords
 }
 
-func (words Words) PickRandomIndex() int {
-	return rand.Intn(len(words))
+// FilterByTypes returns the words that are one of the given types
+func (words Words) FilterByTypes(wordTypes []string) Words {
+	filteredWords := make(Words, 0, len(words))
+	for _, word := range words {
+		for _, wordType := range wordTypes {
+			if word.WordType == wordType {
+				filteredWords = append(filteredWords, word)
+				break
+			}
+		}
+	}
+	return filteredWords
+}
+
+// FilterByExamples returns the words that have example sentences
+func (words Words) FilterByExamples() Words {
+	filteredWords := make(Words, 0, len(words))
+	for _, word := range words {
+		if len(word.Examples) > 0 {
+			filteredWords = append(filteredWords, word)
+		}
+	}
+	return filteredWords
+}
+
+// Without returns the words other than the given word
+func (words Words) Without(word Word) Words {
+	otherWords := make(Words, 0, len(words))
+	for _, otherWord := range words {
+		if otherWord.Word != word.Word {
+			otherWords = append(otherWords, otherWord)
+		}
+	}
+	return otherWords
+}
+
+// WithWordAtRandom puts the word amongst these words at a random position, and returns the words
+// with the position of the word
+func (words Words) WithWordAtRandom(rng *rand.Rand, word Word) (Words, int) {
+	index := rng.Intn(len(words) + 1)
+	withWord := make(Words, 0, len(words)+1)
+	withWord = append(withWord, words[:index]...)
+	withWord = append(withWord, word)
+	return append(withWord, words[index:]...), index
+}
+
+func (words Words) PickRandomIndex(rng *rand.Rand) int {
+	return rng.Intn(len(words))
 }
 
 func (words Words) GetDefinitions() []string {
@@ -2,6 +2,7 @@ package model
 
 import (
 	"math/rand"
+	"reflect"
 	"testing"
 )
 
@@ -22,9 +23,7 @@ func TestWords_PickRandomType(t *testing.T) {
 }
 
 func TestWords_PickRandomWords_NoWords(t *testing.T) {
-	rand.Seed(1)
-
-	got := sampleWords.PickRandomWords(0)
+	got := sampleWords.PickRandomWords(rand.New(rand.NewSource(1)), 0)
 	expected := Words{}
 	if len(got) != len(expected) {
 		t.Errorf("Got length %d and expected %d", len(got), len(expected))
@@ -32,23 +31,19 @@ func TestWords_PickRandomWords_NoWords(t *testing.T) {
 }
 
 func TestWords_PickRandomWords_OneWord(t *testing.T) {
-	rand.Seed(1)
-
-	got := sampleWords.PickRandomWords(1)
+	got := sampleWords.PickRandomWords(rand.New(rand.NewSource(1)), 1)
 	expectedWord := sampleWords[1]
 	expected := Words{expectedWord}
 	if len(got) != len(expected) {
 		t.Errorf("Got length %d and expected %d", len(got), len(expected))
 	}
-	if got[0] != expectedWord {
+	if !reflect.DeepEqual(got[0], expectedWord) {
 		t.Errorf("Got word %s and expected %s", got[0], expectedWord)
 	}
 }
 
 func TestWords_PickRandomWords_PickTooMany(t *testing.T) {
-	rand.Seed(1)
-
-	got := sampleWords.PickRandomWords(5) // Only four words in sample
+	got := sampleWords.PickRandomWords(rand.New(rand.NewSource(1)), 5) // Only four words in sample
 	expectedLength := len(sampleWords)
 	if len(got) != expectedLength {
 		t.Errorf("Got length %d and expected %d", len(got), expectedLength)
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+)
+
+// WordsDiff is the difference between two versions of the corpus
+type WordsDiff struct {
+	Added   Words
+	Removed Words
+	Changed []WordChange
+}
+
+// WordChange is a word in both versions of the corpus, but with different details
+type WordChange struct {
+	From Word
+	To   Word
+}
+
+// DiffWords compares two versions of the corpus. Words are matched by their text and type.
+func DiffWords(from Words, to Words) WordsDiff {
+	diff := WordsDiff{
+		Added:   Words{},
+		Removed: Words{},
+		Changed: []WordChange{},
+	}
+
+	fromByKey := make(map[string]Word, len(from))
+	for _, word := range from {
+		fromByKey[word.diffKey()] = word
+	}
+
+	toKeys := make(map[string]interface{}, len(to))
+	for _, word := range to {
+		key := word.diffKey()
+		toKeys[key] = struct{}{}
+
+		fromWord, present := fromByKey[key]
+		if !present {
+			diff.Added = append(diff.Added, word)
+		} else if !reflect.DeepEqual(fromWord, word) {
+			diff.Changed = append(diff.Changed, WordChange{From: fromWord, To: word})
+		}
+	}
+
+	for _, word := range from {
+		if _, present := toKeys[word.diffKey()]; !present {
+			diff.Removed = append(diff.Removed, word)
+		}
+	}
+
+	return diff
+}
+
+func (d Word) diffKey() string {
+	return strings.ToLower(d.Word) + "|" + strings.ToLower(d.WordType)
+}
@@ -0,0 +1,25 @@
+package model
+
+import "testing"
+
+func TestDiffWords(t *testing.T) {
+	from := Words{
+		Word{Word: "one", WordType: "noun", Definition: "the first"},
+		Word{Word: "two", WordType: "noun", Definition: "the second"},
+	}
+	to := Words{
+		Word{Word: "two", WordType: "noun", Definition: "the number after one"},
+		Word{Word: "three", WordType: "noun", Definition: "the third"},
+	}
+
+	got := DiffWords(from, to)
+	if len(got.Added) != 1 || got.Added[0].Word != "three" {
+		t.Errorf("Got added %v and expected three", got.Added)
+	}
+	if len(got.Removed) != 1 || got.Removed[0].Word != "one" {
+		t.Errorf("Got removed %v and expected one", got.Removed)
+	}
+	if len(got.Changed) != 1 || got.Changed[0].To.Definition != "the number after one" {
+		t.Errorf("Got changed %v and expected two", got.Changed)
+	}
+}
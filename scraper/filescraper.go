@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/ksanta/word-stallion/model"
+	"os"
+)
+
+// formatDetectionLength is how much of the file is looked at to detect its format
+const formatDetectionLength = 512
+
+type FileScraper struct {
+	path     string
+	language string
+}
+
+// NewFileScraper returns an implementation of the Scraper interface that reads words from a
+// local CSV or JSON Lines file. Words that don't say what language they are in are given the
+// language provided.
+func NewFileScraper(path string, language string) Scraper {
+	return &FileScraper{path, language}
+}
+
+func (f *FileScraper) Scrape() chan model.Word {
+	outputChan := make(chan model.Word, 20)
+
+	go func() {
+		defer close(outputChan)
+
+		file, err := os.Open(f.path)
+		if err != nil {
+			fmt.Println("error opening word file:", err)
+			return
+		}
+		defer file.Close()
+
+		reader := bufio.NewReader(file)
+		// Peek errors are ignored as a short file has less to peek at
+		start, _ := reader.Peek(formatDetectionLength)
+		words, wordErrors := model.ReadWords(model.DetectWordListFormat(string(start)), reader)
+		for _, wordError := range wordErrors {
+			fmt.Println("Skipping word in", f.path, wordError)
+		}
+
+		for _, word := range words {
+			if word.Language == "" {
+				word.Language = f.language
+			}
+			// The rest of the game expects word types to have their English names
+			word.WordType = model.CanonicalWordType(word.Language, word.WordType)
+			outputChan <- word
+		}
+	}()
+
+	return outputChan
+}
@@ -11,6 +11,7 @@ import (
 const wotdKey = "wotdKey"
 const wordTypeKey = "wordTypeKey"
 const definitionKey = "definitionKey"
+const examplesKey = "examplesKey"
 
 type MeriamScraper struct {
 	limit int
@@ -49,12 +50,26 @@ func (m *MeriamScraper) Scrape() chan model.Word {
 			})
 		})
 
+		// Scrape the example sentences
+		c.OnHTML("div.wotd-examples p", func(element *colly.HTMLElement) {
+			example := strings.TrimSpace(element.Text)
+			if example == "" {
+				return
+			}
+			examples, _ := element.Request.Ctx.GetAny(examplesKey).([]string)
+			element.Request.Ctx.Put(examplesKey, append(examples, example))
+		})
+
 		c.OnScraped(func(response *colly.Response) {
+			examples, _ := response.Ctx.GetAny(examplesKey).([]string)
+
 			wordEntry := model.Word{
 				Word:       response.Ctx.Get(wotdKey),
 				WordType:   response.Ctx.Get(wordTypeKey),
 				Definition: response.Ctx.Get(definitionKey),
 				URL:        response.Request.URL.String(),
+				Language:   "en",
+				Examples:   examples,
 			}
 			outputChan <- wordEntry
 		})
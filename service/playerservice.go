@@ -5,6 +5,7 @@ import (
 	"github.com/ksanta/word-stallion/dao"
 	"github.com/ksanta/word-stallion/model"
 	"sync"
+	"time"
 )
 
 type PlayerService struct {
@@ -19,28 +20,60 @@ func NewPlayerService(playerDao *dao.PlayerDao, apiDao *dao.ApiDao) *PlayerServi
 	}
 }
 
-func (playerService *PlayerService) SendWelcomeMessageToPlayer(player model.Player, targetScore int, secondsTillStart int) error {
-	welcomeMessage := model.MessageToPlayer{
+func (playerService *PlayerService) SendWelcomeMessageToPlayer(player model.Player, game model.Game, secondsTillStart int) error {
+	return playerService.apiDao.SendMessageToPlayer(player, newWelcomeMessage(player, game, secondsTillStart), "welcome")
+}
+
+// SendWelcomeMessageToActivePlayers lets all waiting players know the rules have changed
+func (playerService *PlayerService) SendWelcomeMessageToActivePlayers(players model.Players, game model.Game, secondsTillStart int) {
+	playerService.sendMessagesToActivePlayers(players, func(player model.Player) interface{} {
+		return newWelcomeMessage(player, game, secondsTillStart)
+	}, "welcome", nil)
+}
+
+func newWelcomeMessage(player model.Player, game model.Game, secondsTillStart int) model.MessageToPlayer {
+	return model.MessageToPlayer{
 		Welcome: &model.Welcome{
 			SecondsTillStart: secondsTillStart,
-			TargetScore:      targetScore,
+			TargetScore:      game.TargetScore,
+			Language:         game.Language,
+			QuestionModes:    game.QuestionModes,
+			Scoring:          game.ScoringDescription(),
+			Teams:            game.Teams,
+			Team:             player.Team,
+			RoundLimit:       game.RoundLimit,
+			TimeLimitSeconds: game.TimeLimitSeconds,
 		},
 	}
-	return playerService.apiDao.SendMessageToPlayer(player, welcomeMessage, "welcome")
 }
 
-func (playerService *PlayerService) SendCorrectAnswerToPlayer(player model.Player, correct bool, correctAnswer int) error {
+func (playerService *PlayerService) SendCorrectAnswerToPlayer(player model.Player, result model.PlayerResult) error {
 	answerMessage := model.MessageToPlayer{
-		PlayerResult: &model.PlayerResult{
-			Correct:       correct,
-			CorrectAnswer: correctAnswer,
-		},
+		PlayerResult: &result,
 	}
 	return playerService.apiDao.SendMessageToPlayer(player, answerMessage, "correct answer")
 }
 
-func (playerService *PlayerService) SendRoundSummaryToActivePlayers(gameId string) (model.Players, error) {
-	players, err := playerService.playerDao.GetPlayers(gameId)
+func (playerService *PlayerService) SendWordsUploadedToPlayer(player model.Player, wordCount int) error {
+	uploadedMessage := model.MessageToPlayer{
+		WordsUploaded: &model.WordsUploaded{
+			WordCount: wordCount,
+		},
+	}
+	return playerService.apiDao.SendMessageToPlayer(player, uploadedMessage, "words uploaded")
+}
+
+func (playerService *PlayerService) SendErrorToPlayer(player model.Player, message string) error {
+	errorMessage := model.MessageToPlayer{
+		Error: &model.GameError{
+			Message: message,
+		},
+	}
+	return playerService.apiDao.SendMessageToPlayer(player, errorMessage, "error")
+}
+
+func (playerService *PlayerService) SendRoundSummaryToActivePlayers(game model.Game) (model.Players, error) {
+	players, err := playerService.playerDao.GetPlayers(game.GameId)
 	if err != nil {
 		return nil, fmt.Errorf("error getting players: %w", err)
 	}
@@ -48,22 +81,43 @@ func (playerService *PlayerService) SendRoundSummaryToActivePlayers(gameId strin
 	roundSummaryMsg := model.MessageToPlayer{
 		RoundSummary: &model.RoundSummary{
 			PlayerStates: players.PlayerStates(),
+			TeamStates:   game.TeamStates(players),
 		},
 	}
 	playerService.sendMessageToActivePlayers(players, roundSummaryMsg, "round summary")
 	return players, nil
 }
 
-func (playerService *PlayerService) SendPlayerUpdateToActivePlayers(players model.Players, state model.PlayerState) error {
+func (playerService *PlayerService) SendPlayerUpdateToActivePlayers(game model.Game, players model.Players, state model.PlayerState) error {
 	roundSummaryMsg := model.MessageToPlayer{
 		RoundSummary: &model.RoundSummary{
 			PlayerStates: []model.PlayerState{state},
+			TeamStates:   game.TeamStates(players),
 		},
 	}
 	playerService.sendMessageToActivePlayers(players, roundSummaryMsg, "round summary")
 	return nil
 }
 
+// SendRoundResultsToActivePlayers closes the round by sending everyone the round summary, along
+// with their own result if they were answering this round
+func (playerService *PlayerService) SendRoundResultsToActivePlayers(game model.Game, players model.Players) {
+	roundSummary := &model.RoundSummary{
+		PlayerStates: players.PlayerStates(),
+		TeamStates:   game.TeamStates(players),
+	}
+	playerService.sendMessagesToActivePlayers(players, func(player model.Player) interface{} {
+		msg := model.MessageToPlayer{
+			RoundSummary: roundSummary,
+		}
+		if player.IsRacing() {
+			result := game.PlayerResult(player)
+			msg.PlayerResult = &result
+		}
+		return msg
+	}, "round results", nil)
+}
+
 func (playerService *PlayerService) SendAboutToStartToActivePlayers(gameId string, startingInSeconds int) (model.Players, error) {
 	players, err := playerService.playerDao.GetPlayers(gameId)
 	if err != nil {
@@ -79,46 +133,139 @@ func (playerService *PlayerService) SendAboutToStartToActivePlayers(gameId strin
 	return players, nil
 }
 
-func (playerService *PlayerService) SendQuestionToActivePlayers(players model.Players, wordsInThisRound model.Words, correctAnswer int, secondsPerQuestion int) error {
-	questionMsg := model.MessageToPlayer{
-		PresentQuestion: &model.PresentQuestion{
-			WordToGuess:    wordsInThisRound[correctAnswer].Word,
-			Definitions:    wordsInThisRound.GetDefinitions(),
-			SecondsAllowed: secondsPerQuestion,
+// SendQuestionToActivePlayers sends each player the question as they see it, keyed by
+// connection id, with the time they are allowed to answer it. Players without a question, such
+// as those eliminated, aren't sent one. When each player's question is sent is recorded before
+// it is posted, as that is when their time to answer starts.
+func (playerService *PlayerService) SendQuestionToActivePlayers(game model.Game, players model.Players, questions map[string]model.Question) error {
+	playerService.sendMessagesToActivePlayers(players, func(player model.Player) interface{} {
+		question, present := questions[player.ConnectionId]
+		if !present {
+			return nil
+		}
+		return model.MessageToPlayer{
+			PresentQuestion: &model.PresentQuestion{
+				Mode:           question.Mode,
+				WordToGuess:    question.WordToGuess,
+				WordType:       question.WordType,
+				Definition:     question.Definition,
+				Sentence:       question.Sentence,
+				Definitions:    question.Options,
+				SecondsAllowed: player.SecondsAllowed(game.SecondsPerQuestion),
+				SuddenDeath:    game.SuddenDeath,
+			},
+		}
+	}, "question", func(player model.Player, sentAt time.Time) {
+		err := playerService.playerDao.SetQuestionSentAt(player.ConnectionId, sentAt)
+		if err != nil {
+			fmt.Println("Error recording when the question was sent", err)
+		}
+	})
+	return nil
+}
+
+// SendPowerUpsToPlayer lets the player know which power-ups they hold and have queued
+func (playerService *PlayerService) SendPowerUpsToPlayer(player model.Player) error {
+	powerUpsMessage := model.MessageToPlayer{
+		PowerUpsChanged: &model.PowerUpsChanged{
+			PowerUps:       player.PowerUps,
+			QueuedPowerUps: player.QueuedPowerUps,
 		},
 	}
-	playerService.sendMessageToActivePlayers(players, questionMsg, "question")
-	return nil
+	return playerService.apiDao.SendMessageToPlayer(player, powerUpsMessage, "power-ups")
 }
 
-func (playerService *PlayerService) SendGameSummaryToAllActivePlayers(players model.Players) error {
-	winner := players.PlayerWithHighestPoints()
-	msg := model.MessageToPlayer{
-		Summary: &model.Summary{
-			Winner: winner.Name,
-			Icon:   winner.Icon,
+// SendDailyLeaderboardToPlayer shows the player how everyone has done in a daily challenge
+func (playerService *PlayerService) SendDailyLeaderboardToPlayer(player model.Player, challenge *model.DailyChallenge) error {
+	leaderboard := challenge.Leaderboard()
+	leaderboardMsg := model.MessageToPlayer{
+		DailyLeaderboard: &leaderboard,
+	}
+	return playerService.apiDao.SendMessageToPlayer(player, leaderboardMsg, "daily leaderboard")
+}
+
+// SendEliminatedToActivePlayers lets everyone know who has been knocked out of the race
+func (playerService *PlayerService) SendEliminatedToActivePlayers(players model.Players, eliminated model.Player) {
+	eliminatedMsg := model.MessageToPlayer{
+		Eliminated: &model.Eliminated{
+			Player:      eliminated.PlayerState(),
+			Round:       eliminated.EliminatedInRound,
+			PlayersLeft: players.NumRacing(),
+		},
+	}
+	playerService.sendMessageToActivePlayers(players, eliminatedMsg, "eliminated")
+}
+
+// SendSuddenDeathToActivePlayers lets everyone know who is racing in sudden death
+func (playerService *PlayerService) SendSuddenDeathToActivePlayers(players model.Players, inSuddenDeath model.Players) {
+	suddenDeathMsg := model.MessageToPlayer{
+		SuddenDeath: &model.SuddenDeath{
+			Players: inSuddenDeath.PlayerStates(),
 		},
 	}
+	playerService.sendMessageToActivePlayers(players, suddenDeathMsg, "sudden death")
+}
+
+// SendGameSummaryToAllActivePlayers lets everyone know who won. In team races the winner is the
+// team with the highest score.
+func (playerService *PlayerService) SendGameSummaryToAllActivePlayers(game model.Game, players model.Players) error {
+	summary := &model.Summary{
+		Rankings: players.Rankings(game.TieBreaker),
+	}
+	if game.IsTeamRace() {
+		summary.Winner = game.LeadingTeam(players).Name
+		summary.TeamWon = true
+	} else if len(summary.Rankings) > 0 {
+		winner := summary.Rankings[0]
+		summary.Winner = winner.Name
+		summary.Icon = winner.Icon
+		summary.Tie = winner.Tied
+	}
+	if game.IsPractice() {
+		summary.Review = game.PracticeReview()
+	}
+	msg := model.MessageToPlayer{
+		Summary: summary,
+	}
 	playerService.sendMessageToActivePlayers(players, msg, "game summary")
 	return nil
 }
 
 func (playerService *PlayerService) sendMessageToActivePlayers(players model.Players, message interface{}, messageType string) {
+	playerService.sendMessagesToActivePlayers(players, func(model.Player) interface{} {
+		return message
+	}, messageType, nil)
+}
+
+// sendMessagesToActivePlayers sends each active player their own message. Players with a nil
+// message are skipped. If given, beforeSend is called with the time each message is sent, before
+// it is posted, so the player can't reply before it has been called.
+func (playerService *PlayerService) sendMessagesToActivePlayers(players model.Players, messageFor func(model.Player) interface{},
+	messageType string, beforeSend func(player model.Player, sentAt time.Time)) {
 	waitGroup := sync.WaitGroup{}
 
 	for _, player := range players {
-		if player.Active {
-			waitGroup.Add(1)
-			// Make a copy so goroutine will pick out the correct connection id
-			playerCopy := player
-			go func() {
-				defer waitGroup.Done()
-				err := playerService.apiDao.SendMessageToPlayer(*playerCopy, message, messageType)
-				if err != nil {
-					fmt.Println("Error posting message to player", err)
-				}
-			}()
+		if !player.Active {
+			continue
 		}
+		message := messageFor(*player)
+		if message == nil {
+			continue
+		}
+
+		waitGroup.Add(1)
+		// Make a copy so goroutine will pick out the correct connection id
+		playerCopy := player
+		go func() {
+			defer waitGroup.Done()
+			if beforeSend != nil {
+				beforeSend(*playerCopy, time.Now())
+			}
+			err := playerService.apiDao.SendMessageToPlayer(*playerCopy, message, messageType)
+			if err != nil {
+				fmt.Println("Error posting message to player", err)
+			}
+		}()
 	}
 
 	waitGroup.Wait()
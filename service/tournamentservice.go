@@ -0,0 +1,186 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ksanta/word-stallion/dao"
+	"github.com/ksanta/word-stallion/model"
+	"sync"
+)
+
+type TournamentService struct {
+	tournamentDao           *dao.TournamentDao
+	gameDao                 *dao.GameDao
+	playerDao               *dao.PlayerDao
+	apiDao                  *dao.ApiDao
+	functionDao             *dao.FunctionDao
+	doStartGameFunctionName string
+}
+
+func NewTournamentService(tournamentDao *dao.TournamentDao, gameDao *dao.GameDao, playerDao *dao.PlayerDao,
+	apiDao *dao.ApiDao, functionDao *dao.FunctionDao, doStartGameFunctionName string) *TournamentService {
+	return &TournamentService{
+		tournamentDao:           tournamentDao,
+		gameDao:                 gameDao,
+		playerDao:               playerDao,
+		apiDao:                  apiDao,
+		functionDao:             functionDao,
+		doStartGameFunctionName: doStartGameFunctionName,
+	}
+}
+
+// maxTournamentUpdateAttempts limits how many times an update is retried when others are
+// updating the tournament at the same time
+const maxTournamentUpdateAttempts = 5
+
+// ErrTournamentNotFound is returned when updating a tournament that doesn't exist
+var ErrTournamentNotFound = errors.New("tournament not found")
+
+// UpdateTournament fetches the tournament, applies the update and saves it. The update is
+// applied again to a fresh copy if someone else saved the tournament in the meantime.
+func (tournamentService *TournamentService) UpdateTournament(tournamentId string, update func(*model.Tournament) error) (*model.Tournament, error) {
+	for attempt := 0; attempt < maxTournamentUpdateAttempts; attempt++ {
+		tournament, err := tournamentService.tournamentDao.GetTournament(tournamentId)
+		if err != nil {
+			return nil, fmt.Errorf("error getting tournament: %w", err)
+		}
+		if tournament == nil {
+			return nil, ErrTournamentNotFound
+		}
+
+		err = update(tournament)
+		if err != nil {
+			return nil, err
+		}
+
+		saved, err := tournamentService.tournamentDao.PutTournament(tournament)
+		if err != nil {
+			return nil, fmt.Errorf("error saving tournament: %w", err)
+		}
+		if saved {
+			return tournament, nil
+		}
+		fmt.Println("Tournament was updated by someone else - retrying")
+	}
+	return nil, fmt.Errorf("gave up updating tournament %s after %d attempts", tournamentId, maxTournamentUpdateAttempts)
+}
+
+// WithdrawDisconnected withdraws the entrants whose connection has gone, so they aren't seeded
+// into another heat
+func (tournamentService *TournamentService) WithdrawDisconnected(tournament *model.Tournament) error {
+	for _, entrant := range tournament.Entrants {
+		if entrant.Withdrawn {
+			continue
+		}
+		connected, err := tournamentService.apiDao.IsConnected(entrant.ConnectionId)
+		if err != nil {
+			return fmt.Errorf("error checking connection of %s: %w", entrant.Name, err)
+		}
+		if !connected {
+			fmt.Println(entrant.Name, "has disconnected - withdrawing from tournament")
+			tournament.Withdraw(entrant.ConnectionId)
+		}
+	}
+	return nil
+}
+
+// StartHeats creates a game for each heat in the current round, puts the seeded players into it
+// and starts it. Heats that already have a game are left alone. Players whose connection has gone
+// are left out, and heats with nobody left to play finish without anyone advancing.
+func (tournamentService *TournamentService) StartHeats(tournament *model.Tournament) error {
+	round := tournament.CurrentRound()
+	if round == nil || tournament.State != model.TournamentInProgress {
+		return nil
+	}
+
+	abandonedHeats := make([]string, 0)
+	for _, heat := range round.Heats {
+		if heat.Finished {
+			continue
+		}
+		existingGame, err := tournamentService.gameDao.GetGame(heat.GameId)
+		if err != nil {
+			return fmt.Errorf("error getting heat %s: %w", heat.GameId, err)
+		}
+		if existingGame != nil {
+			continue
+		}
+
+		entrants := make([]model.Entrant, 0, len(heat.Entrants))
+		for _, connectionId := range heat.Entrants {
+			entrant, _ := tournament.Entrant(connectionId)
+			connected, err := tournamentService.apiDao.IsConnected(connectionId)
+			if err != nil {
+				return fmt.Errorf("error checking connection of %s: %w", entrant.Name, err)
+			}
+			if !connected {
+				fmt.Println(entrant.Name, "has disconnected - leaving them out of heat", heat.GameId)
+				continue
+			}
+			entrants = append(entrants, entrant)
+		}
+		if len(entrants) == 0 {
+			abandonedHeats = append(abandonedHeats, heat.GameId)
+			continue
+		}
+
+		fmt.Println("Creating heat", heat.GameId, "with", len(entrants), "players")
+		game, err := tournamentService.gameDao.CreateTournamentGame(heat.GameId, tournament.TournamentId)
+		if err != nil {
+			return fmt.Errorf("error creating heat %s: %w", heat.GameId, err)
+		}
+
+		// Players join in seed order, so the top seed is the heat's creator
+		for seed, entrant := range entrants {
+			_, err := tournamentService.playerDao.AddNewPlayer(entrant.ConnectionId, game.GameId, int64(seed), entrant.Name, entrant.Icon, "", "")
+			if err != nil {
+				return fmt.Errorf("error adding %s to heat %s: %w", entrant.Name, heat.GameId, err)
+			}
+		}
+
+		err = tournamentService.functionDao.InvokeStartGame(tournamentService.doStartGameFunctionName, game.GameId)
+		if err != nil {
+			return fmt.Errorf("error starting heat %s: %w", heat.GameId, err)
+		}
+	}
+	if len(abandonedHeats) == 0 {
+		return nil
+	}
+
+	// Nobody is left to play these heats, which may finish the round
+	fmt.Println("Nobody is left to play heats", abandonedHeats)
+	updated, err := tournamentService.UpdateTournament(tournament.TournamentId, func(tournament *model.Tournament) error {
+		for _, gameId := range abandonedHeats {
+			tournament.RecordHeatResult(gameId, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error recording abandoned heats: %w", err)
+	}
+	*tournament = *updated
+	return tournamentService.StartHeats(tournament)
+}
+
+// SendBracketToParticipants lets everyone following the tournament know how it is going
+func (tournamentService *TournamentService) SendBracketToParticipants(tournament *model.Tournament) {
+	bracket := tournament.Bracket()
+	bracketMsg := model.MessageToPlayer{
+		Bracket: &bracket,
+	}
+
+	waitGroup := sync.WaitGroup{}
+	for _, connectionId := range tournament.Participants() {
+		waitGroup.Add(1)
+		// Messages are addressed by connection id, so a player is all that's needed
+		participant := model.Player{ConnectionId: connectionId}
+		go func() {
+			defer waitGroup.Done()
+			err := tournamentService.apiDao.SendMessageToPlayer(participant, bracketMsg, "bracket")
+			if err != nil {
+				fmt.Println("Error posting message to participant", err)
+			}
+		}()
+	}
+	waitGroup.Wait()
+}
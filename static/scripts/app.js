@@ -3,6 +3,35 @@ const API_IP = "c085yoxin0.execute-api.ap-southeast-2.amazonaws.com";
 var snd = new Audio('./bugle.wav');
 var victory = new Audio('./victory.mp3');
 
+// The score that wins the race, which the server sends when the player joins
+var targetScore = 500;
+
+// Power-ups the player holds, and those that will take effect in the next round
+var powerUps = [];
+var queuedPowerUps = [];
+// Power-ups can't be used once the current question has been answered
+var answered = false;
+
+// When the current question arrived, so the server can tell how long the player took to answer
+// apart from how long the question took to reach them
+var questionReceivedAt = 0;
+var answerMillis = function () {
+    return Math.round(performance.now() - questionReceivedAt);
+};
+var powerUpNames = {
+    FIFTY_FIFTY: "50/50",
+    DOUBLE_POINTS: "Double points",
+    TIME_FREEZE: "Time freeze"
+};
+
+// The id that identifies the player between visits, so they can be ranked on the daily
+// leaderboard and reviewed on the words they have missed
+var playerId = localStorage.getItem('playerId');
+if (!playerId) {
+    playerId = Date.now().toString(36) + Math.random().toString(36).substring(2);
+    localStorage.setItem('playerId', playerId);
+}
+
 $(document).ready(function () {
     $('#whoWon').hide();
     $('#countDownBox').hide();
@@ -16,21 +45,80 @@ $(document).ready(function () {
         $(this).addClass('horse-selected'); // adds the class to the clicked image
     });
 
-    $('.definition').click(function () {
+    // Options are added for each question, so clicks are handled by the container
+    $('#options').on('click', '.definition', function () {
         $(this).addClass('alt-selected'); // adds the class to the clicked image
 
         const response = $(this).data('option')
         let message = {
             MessageType: "playerresponse",
             PlayerResponse: {
-                Response: response
+                Response: response,
+                AnswerMillis: answerMillis()
             }
         };
         connection.send(JSON.stringify(message));
         $('.definition').css("pointer-events", "none")
+        answered = true;
+        showPowerUps();
+    });
+
+    // Sends the typed word in type-in rounds
+    $('#type-in').on('submit', function (event) {
+        event.preventDefault();
+        const text = $('#typeInEntry').val().trim();
+        if (!text) {
+            return
+        }
+
+        let message = {
+            MessageType: "playerresponse",
+            PlayerResponse: {
+                Text: text,
+                AnswerMillis: answerMillis()
+            }
+        };
+        connection.send(JSON.stringify(message));
+        $('#type-in :input').prop('disabled', true);
+        answered = true;
+        showPowerUps();
+    });
+
+    // Spends a power-up on the next round
+    $('#power-ups').on('click', '.use-power-up', function () {
+        let message = {
+            MessageType: "usepowerup",
+            UsePowerUp: {
+                PowerUp: $(this).data('power-up')
+            }
+        };
+        connection.send(JSON.stringify(message));
     });
 
-    // Initialises game with players' chosen preferences
+    // Registers for a tournament, or follows it without playing
+    $('.join-tournament, .watch-tournament').on('click', function () {
+        const tournamentId = document.getElementById("tournamentEntry").value;
+        const spectate = $(this).hasClass('watch-tournament');
+        if (!tournamentId || (!spectate && (!document.getElementById("nameEntryOne").value || $('.horse-selected').length === 0))) {
+            return
+        }
+
+        $('#selections').hide();
+
+        let message = {
+            MessageType: "jointournament",
+            JoinTournament: {
+                TournamentId: tournamentId,
+                Name: document.getElementById("nameEntryOne").value,
+                Icon: spectate ? "" : $('.horse-selected')[0].id,
+                Spectate: spectate
+            }
+        };
+        connection.send(JSON.stringify(message))
+    });
+
+    // Initialises game with players' chosen preferences. Buttons with a mode start a solo game of
+    // that kind instead of joining a race.
     $('.submit').on('click', function () {
         if (!document.getElementById("nameEntryOne").value || $('.horse-selected')[0].id == undefined) {
             return
@@ -42,9 +130,15 @@ $(document).ready(function () {
             MessageType: "newplayer",
             NewPlayer: {
                 Name: document.getElementById("nameEntryOne").value,
-                Icon: $('.horse-selected')[0].id
+                Icon: $('.horse-selected')[0].id,
+                Team: document.getElementById("teamEntry").value,
+                PlayerId: playerId
             }
         };
+        const mode = $(this).data('mode');
+        if (mode) {
+            message.NewPlayer[mode] = true;
+        }
         connection.send(JSON.stringify(message))
     });
 });
@@ -53,6 +147,12 @@ $(document).ready(function () {
 var showWaiting = function(welcome) {
     $('#waitingForPlayersBox').show()
     console.log("Starting in" + welcome.SecondsTillStart)
+    if (welcome.TargetScore) {
+        targetScore = welcome.TargetScore;
+    }
+    if (welcome.Team) {
+        $('#yourTeam').text("You are racing for " + welcome.Team);
+    }
 }
 
 function displayWinner(win, pic) {
@@ -61,7 +161,18 @@ function displayWinner(win, pic) {
     victory.currentTime = 0;
 
     $('#winnerName').text(win);
-    $('#winPic').html("<img src=" + pic + ">");
+    if (pic) {
+        $('#winPic').html("<img src=" + pic + ">");
+    } else {
+        $('#winPic').empty();
+    }
+}
+
+// trackPosition returns how far along the track a score is, as a percentage
+function trackPosition(score) {
+    const maxPosition = 100;
+    let position = Math.floor(score / targetScore * maxPosition);
+    return Math.max(0, Math.min(position, maxPosition));
 }
 
 //Variables to initialize
@@ -73,6 +184,10 @@ connection.onerror = function (error) {
 };
 
 var showCountdown = function () {
+    // Tournament players race again in the next heat, so the last race is cleared away
+    $('#whoWon').hide();
+    $('#tracks .track').not('#template-track, #template-team-track').remove();
+
     $('#waitingForPlayersBox').hide()
     $('#countDownBox').show();
     snd.play();
@@ -96,15 +211,36 @@ var showCountdown = function () {
 };
 
 var showQuestion = function (question) {
-    let definitions = $('.definition')
-    definitions.removeClass('alt-selected'); // removes the previous selected class
-    definitions.css('background-color', 'white')
-    definitions.css('pointer-events', 'auto')
-
+    questionReceivedAt = performance.now();
     $('#word-to-guess').text(question.WordToGuess);
-    $('#definition0').text(question.Definitions[0]);
-    $('#definition1').text(question.Definitions[1]);
-    $('#definition2').text(question.Definitions[2]);
+    $('#sudden-death').toggle(!!question.SuddenDeath);
+    $('#question-hint').text(question.Definition || question.Sentence || "");
+    $('#correct-word').empty();
+
+    // Add an option for each definition, as the number of options varies between questions
+    const options = $('#options').empty();
+    const definitions = question.Definitions || [];
+    for (let i = 0; i < definitions.length; i++) {
+        $('<div class="definition"></div>')
+            .attr('id', 'definition' + i)
+            .attr('data-option', i)
+            .text(definitions[i])
+            .appendTo(options);
+    }
+
+    // Type-in rounds have no options, so the word is typed instead
+    if (question.Mode === "TYPE_IN") {
+        $('#type-in').show();
+        $('#type-in :input').prop('disabled', false);
+        $('#typeInEntry').val('').focus();
+    } else {
+        $('#type-in').hide();
+    }
+
+    // Queued power-ups are in effect for this question
+    queuedPowerUps = [];
+    answered = false;
+    showPowerUps();
 
     $('#question-area').show();
 };
@@ -131,9 +267,9 @@ var updateGame = function (summary) {
                 .attr('id', 'player' + player.Id)
         }
 
-        // Set the player name
+        // Set the player name, and their team in team races
         const playerSpan = $('#player' + player.Id)
-        playerSpan.text(player.Name);
+        playerSpan.text(player.Team ? player.Name + " (" + player.Team + ")" : player.Name);
 
         // Set the horse icon
         let horseIcon = player.Icon;
@@ -143,19 +279,166 @@ var updateGame = function (summary) {
         const horse = $("#horse" + player.Id)
         horse.attr('src', 'images/' + horseIcon + '.png')
 
+        // Players knocked out of an elimination race are still shown, but no longer race
+        track.toggleClass('eliminated', !!player.Eliminated);
+
         // Set the horse position
-        const targetPoints = 500;
-        const maxPosition = 100;
-        let position = Math.floor(player.Score / targetPoints * maxPosition);
-        position = Math.min(position, maxPosition);
+        horse.animate({left: trackPosition(player.Score) + "%"}, "slow");
+    }
+
+    if (summary.TeamStates) {
+        updateTeams(summary.TeamStates);
+    }
+};
+
+// Updates the placement of each team in team races. The target score applies to the team.
+var updateTeams = function (teamStates) {
+    for (let i = 0; i < teamStates.length; i++) {
+        const team = teamStates[i];
+
+        let track = $('.team-track').filter(function () {
+            return $(this).data('team') === team.Name;
+        });
+
+        // Create a new track if this is a new team
+        if (track.length === 0) {
+            track = $('#template-team-track')
+                .clone()
+                .appendTo('#team-tracks')
+                .removeAttr('id')
+                .data('team', team.Name)
+                .css('display', 'flex')
+        }
 
-        horse.animate({left: position + "%"}, "slow");
+        track.find('.team-name').text(team.Name + " (" + team.Members + ")");
+        track.find('.team-marker')
+            .text(team.Score)
+            .animate({left: trackPosition(team.Score) + "%"}, "slow");
     }
 };
 
 var endGame = function (summary) {
     $('#question-area').hide()
-    displayWinner(summary.Winner, "images/" + summary.Icon + ".png")
+    if (summary.Review) {
+        showReview(summary.Review)
+    } else if (summary.TeamWon) {
+        displayWinner("Team " + summary.Winner)
+    } else {
+        displayWinner(summary.Winner, "images/" + summary.Icon + ".png")
+    }
+};
+
+// showReview lists each word asked in a practice game, and whether the player got it right
+var showReview = function (review) {
+    $('#reviewBox').show();
+    const rows = $('#reviewRows').empty();
+    for (let i = 0; i < review.length; i++) {
+        const word = review[i];
+        let wordCell = $('<td></td>').text(word.Word);
+        if (word.URL) {
+            wordCell = $('<td></td>').append($('<a target="_blank"></a>').attr('href', word.URL).text(word.Word));
+        }
+        $('<tr></tr>')
+            .addClass(word.Correct ? 'table-success' : 'table-danger')
+            .append(wordCell)
+            .append($('<td></td>').text(word.Definition))
+            .append($('<td></td>').text(word.Correct ? "Right" : "Wrong"))
+            .appendTo(rows);
+    }
+};
+
+// showPowerUps shows a button for each power-up the player holds, and those already in use
+var showPowerUps = function () {
+    const area = $('#power-ups').empty();
+    for (let i = 0; i < powerUps.length; i++) {
+        $('<button type="button" class="btn btn-warning use-power-up"></button>')
+            .attr('data-power-up', powerUps[i])
+            .text(powerUpNames[powerUps[i]] || powerUps[i])
+            .prop('disabled', answered)
+            .appendTo(area);
+    }
+    if (queuedPowerUps.length > 0) {
+        const names = queuedPowerUps.map(function (powerUp) {
+            return powerUpNames[powerUp] || powerUp;
+        });
+        $('<div></div>')
+            .text("Next round: " + names.join(", "))
+            .appendTo(area);
+    }
+};
+
+var updatePowerUps = function (powerUpsChanged) {
+    powerUps = powerUpsChanged.PowerUps || [];
+    queuedPowerUps = powerUpsChanged.QueuedPowerUps || [];
+    showPowerUps();
+};
+
+// showNotice tells the player about something happening in the race for a few seconds
+var showNotice = function (text) {
+    $('#noticeMessage').text(text);
+    $('#noticeBox').stop(true, true).show().delay(3000).fadeOut();
+};
+
+var showEliminated = function (eliminated) {
+    let text = eliminated.Player.Name + " has been knocked out in round " + eliminated.Round + "!";
+    if (eliminated.PlayersLeft > 1) {
+        text += " " + eliminated.PlayersLeft + " horses are left.";
+    }
+    showNotice(text);
+};
+
+var showSuddenDeath = function (suddenDeath) {
+    const names = suddenDeath.Players.map(function (player) {
+        return player.Name;
+    });
+    showNotice("Sudden death! " + names.join(" and ") + " race on until one of them answers correctly first.");
+};
+
+// showBracket shows each round of the tournament, with the players who advanced from each heat
+var showBracket = function (bracket) {
+    $('#bracketBox').show();
+    $('#bracketName').text(bracket.Name + " (" + bracket.Entrants + " players)");
+
+    const rounds = $('#bracketRounds').empty();
+    for (let i = 0; i < bracket.Rounds.length; i++) {
+        const round = $('<div class="bracket-round"></div>')
+            .append($('<div></div>').text("Round " + (i + 1)))
+            .appendTo(rounds);
+
+        for (let j = 0; j < bracket.Rounds[i].length; j++) {
+            const heat = bracket.Rounds[i][j];
+            const heatDiv = $('<div class="bracket-heat"></div>').appendTo(round);
+            for (let k = 0; k < heat.Players.length; k++) {
+                $('<div></div>')
+                    .text(heat.Players[k])
+                    .toggleClass('bracket-advanced', heat.Advanced.indexOf(heat.Players[k]) >= 0)
+                    .appendTo(heatDiv);
+            }
+            if (!heat.Finished) {
+                $('<div></div>').text("Racing...").appendTo(heatDiv);
+            }
+        }
+    }
+
+    $('#bracketWinner').text(bracket.Winner ? bracket.Winner + " wins the tournament!" : "");
+};
+
+// showDailyLeaderboard ranks everyone who has played today's daily challenge
+var showDailyLeaderboard = function (leaderboard) {
+    $('#leaderboardBox').show();
+    $('#leaderboardDate').text("Daily challenge for " + leaderboard.Date);
+
+    const rows = $('#leaderboardRows').empty();
+    for (let i = 0; i < leaderboard.Rankings.length; i++) {
+        const ranking = leaderboard.Rankings[i];
+        $('<tr></tr>')
+            .append($('<td></td>').text(ranking.Position))
+            .append($('<td></td>').text(ranking.Name))
+            .append($('<td></td>').text(ranking.Points))
+            .append($('<td></td>').text(ranking.CorrectAnswers))
+            .append($('<td></td>').text((ranking.TotalResponseMillis / 1000).toFixed(1) + "s"))
+            .appendTo(rows);
+    }
 };
 
 var showError = function (message) {
@@ -173,6 +456,23 @@ var showResult = function (playerResult) {
     if (!playerResult.Correct) {
         $('.alt-selected').css('background-color', 'red')
     }
+
+    // A streak of correct answers can earn a power-up
+    if (playerResult.PowerUpEarned) {
+        powerUps.push(playerResult.PowerUpEarned);
+        showPowerUps();
+    }
+
+    // Type-in rounds show the word that had to be typed
+    if (playerResult.CorrectWord) {
+        let text = playerResult.CorrectWord;
+        if (playerResult.NearMiss) {
+            text = "Close! It was " + text;
+        }
+        $('#correct-word')
+            .text(text)
+            .css('color', playerResult.Correct ? 'green' : 'red');
+    }
 }
 
 connection.onmessage = function (wsMessage) {
@@ -180,27 +480,44 @@ connection.onmessage = function (wsMessage) {
         console.log("Received: " + wsMessage.data);
         let data = JSON.parse(wsMessage.data);
 
+        // A message can carry more than one part, such as the round summary and the player's
+        // result when results are withheld until the round closes
         if (data.hasOwnProperty('Welcome')) {
             showWaiting(data.Welcome)
-
-        } else if (data.hasOwnProperty('Error')) {
+        }
+        if (data.hasOwnProperty('Error')) {
             showError(data.Error)
-
-        } else if (data.hasOwnProperty('AboutToStart')) {
+        }
+        if (data.hasOwnProperty('AboutToStart')) {
             showCountdown(data.AboutToStart)
-
-        } else if (data.hasOwnProperty('PresentQuestion')) {
+        }
+        if (data.hasOwnProperty('PresentQuestion')) {
             showQuestion(data.PresentQuestion)
-
-        } else if (data.hasOwnProperty('RoundSummary')) {
+        }
+        if (data.hasOwnProperty('RoundSummary')) {
             updateGame(data.RoundSummary)
-
-        } else if (data.hasOwnProperty('Summary')) {
+        }
+        if (data.hasOwnProperty('Summary')) {
             endGame(data.Summary)
-
-        } else if (data.hasOwnProperty('PlayerResult')) {
+        }
+        if (data.hasOwnProperty('PlayerResult')) {
             showResult(data.PlayerResult)
         }
+        if (data.hasOwnProperty('PowerUpsChanged')) {
+            updatePowerUps(data.PowerUpsChanged)
+        }
+        if (data.hasOwnProperty('Eliminated')) {
+            showEliminated(data.Eliminated)
+        }
+        if (data.hasOwnProperty('SuddenDeath')) {
+            showSuddenDeath(data.SuddenDeath)
+        }
+        if (data.hasOwnProperty('Bracket')) {
+            showBracket(data.Bracket)
+        }
+        if (data.hasOwnProperty('DailyLeaderboard')) {
+            showDailyLeaderboard(data.DailyLeaderboard)
+        }
 
     } catch (e) {
         console.log(e);